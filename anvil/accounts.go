@@ -0,0 +1,223 @@
+package anvil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultMnemonic is the mnemonic Anvil derives its dev accounts from unless
+// another one is configured.
+const DefaultMnemonic = "test test test test test test test test test test test junk"
+
+// DefaultDerivationPath is the BIP32 path prefix Anvil uses for its dev
+// accounts. The account index is appended to it.
+const DefaultDerivationPath = "m/44'/60'/0'/0/"
+
+// defaultAccounts is the number of dev accounts Anvil creates by default.
+const defaultAccounts = 10
+
+// DevAccount is one of the funded dev accounts of an Anvil node.
+type DevAccount struct {
+	Address    common.Address
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// DeriveAccounts derives n accounts from a BIP39 mnemonic the way Anvil does:
+// account i uses the derivation path followed directly by the decimal index
+// i, so the path normally ends in "/". An empty path means
+// DefaultDerivationPath.
+func DeriveAccounts(mnemonic, path string, n uint) ([]DevAccount, error) {
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+
+	seed, err := mnemonicSeed(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	master, err := newMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]DevAccount, 0, n)
+	for i := uint(0); i < n; i++ {
+		indexes, err := parseDerivationPath(path + strconv.FormatUint(uint64(i), 10))
+		if err != nil {
+			return nil, err
+		}
+		key, err := master.derive(indexes)
+		if err != nil {
+			return nil, err
+		}
+		priv, err := crypto.ToECDSA(key.key)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, DevAccount{Address: crypto.PubkeyToAddress(priv.PublicKey), PrivateKey: priv})
+	}
+	return accounts, nil
+}
+
+// configAccounts derives the dev accounts Anvil creates for config. It
+// returns nil if they cannot be derived locally, i.e. when Anvil picks a
+// random mnemonic.
+func configAccounts(config *Config) ([]DevAccount, error) {
+	if config.mnemonicRandom || config.mnemonicSeedUnsafe != "" {
+		return nil, nil
+	}
+
+	mnemonic := config.mnemonic
+	if mnemonic == "" {
+		mnemonic = DefaultMnemonic
+	}
+	n := config.accounts
+	if n == 0 {
+		n = defaultAccounts
+	}
+	return DeriveAccounts(mnemonic, config.derivationPath, n)
+}
+
+// hardenedOffset is added to the index of hardened BIP32 children.
+const hardenedOffset = 0x80000000
+
+// parseDerivationPath parses a BIP32 path such as "m/44'/60'/0'/0/1".
+func parseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m/", path)
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		part = strings.TrimRight(part, "'h")
+		index, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid component %q in derivation path %q", part, path)
+		}
+		if hardened {
+			index += hardenedOffset
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+// mnemonicSeed returns the BIP39 seed of mnemonic. Anvil only accepts
+// English mnemonics, which are unaffected by the NFKD normalization BIP39
+// requires, so the words are only re-joined with single spaces.
+func mnemonicSeed(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	seed, err := pbkdf2.Key(sha512.New, mnemonic, []byte("mnemonic"+passphrase), 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving seed: %w", err)
+	}
+	return seed, nil
+}
+
+// extendedKey is a BIP32 extended private key.
+type extendedKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+// newMasterKey returns the BIP32 master key of seed.
+func newMasterKey(seed []byte) (extendedKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return extendedKey{}, errors.New("seed yields an invalid master key")
+	}
+	return extendedKey{key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+// derive derives the descendant of k at the given child indexes.
+func (k extendedKey) derive(indexes []uint32) (extendedKey, error) {
+	var err error
+	for _, index := range indexes {
+		if k, err = k.child(index); err != nil {
+			return extendedKey{}, err
+		}
+	}
+	return k, nil
+}
+
+// child derives the child key with the given index.
+func (k extendedKey) child(index uint32) (extendedKey, error) {
+	mac := hmac.New(sha512.New, k.chainCode)
+	if index >= hardenedOffset {
+		mac.Write([]byte{0})
+		mac.Write(k.key)
+	} else {
+		priv, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return extendedKey{}, err
+		}
+		mac.Write(crypto.CompressPubkey(&priv.PublicKey))
+	}
+	mac.Write(binary.BigEndian.AppendUint32(nil, index))
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return extendedKey{}, fmt.Errorf("invalid child key at index %d", index)
+	}
+	key := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	key.Mod(key, n)
+	if key.Sign() == 0 {
+		return extendedKey{}, fmt.Errorf("invalid child key at index %d", index)
+	}
+	return extendedKey{key: common.LeftPadBytes(key.Bytes(), 32), chainCode: sum[32:]}, nil
+}
+
+// Accounts returns the dev accounts of the node. For nodes started with a
+// random mnemonic it returns nil unless SetStartupReport is enabled. Handles
+// created with Connect assume the node uses Anvil's default mnemonic.
+func (a *Anvil) Accounts() []DevAccount {
+	return a.accounts
+}
+
+// TransactOpts returns transaction options signing with the private key of
+// dev account i and the chain ID reported by the node.
+func (a Anvil) TransactOpts(i int) (*bind.TransactOpts, error) {
+	return a.TransactOptsContext(context.Background(), i)
+}
+
+// TransactOptsContext is like TransactOpts but honors ctx for cancellation and deadlines.
+// The returned options carry ctx as their Context.
+func (a Anvil) TransactOptsContext(ctx context.Context, i int) (*bind.TransactOpts, error) {
+	if i < 0 || i >= len(a.accounts) {
+		return nil, fmt.Errorf("no dev account %d, node has %d", i, len(a.accounts))
+	}
+
+	chainID, err := makeRequest[hexutil.Big](ctx, a.rpcClient, "eth_chainId", []any{})
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := bind.NewKeyedTransactorWithChainID(a.accounts[i].PrivateKey, (*big.Int)(chainID))
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+	return opts, nil
+}
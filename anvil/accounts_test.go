@@ -0,0 +1,116 @@
+package anvil
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestAnvil_DeriveAccounts checks the derivation against Anvil's well-known dev accounts.
+func TestAnvil_DeriveAccounts(t *testing.T) {
+	accounts, err := DeriveAccounts(DefaultMnemonic, "", 2)
+	if err != nil {
+		t.Fatalf("DeriveAccounts failed: %v", err)
+	}
+
+	want := []struct {
+		address common.Address
+		key     string
+	}{
+		{common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"), "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"},
+		{common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"), "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d"},
+	}
+	for i, w := range want {
+		if accounts[i].Address != w.address {
+			t.Fatalf("account %d is %s, expected %s", i, accounts[i].Address, w.address)
+		}
+		if key := common.Bytes2Hex(crypto.FromECDSA(accounts[i].PrivateKey)); key != w.key {
+			t.Fatalf("account %d has key %s, expected %s", i, key, w.key)
+		}
+	}
+
+	if _, err := DeriveAccounts(DefaultMnemonic, "m/44'/x/0", 1); err == nil {
+		t.Fatalf("DeriveAccounts accepted an invalid path")
+	}
+
+	// Like Anvil, the index is appended to the path as is: account 0 of
+	// "m/44'/60'/0'/1" is at "m/44'/60'/0'/10".
+	accounts, err = DeriveAccounts(DefaultMnemonic, "m/44'/60'/0'/1", 1)
+	if err != nil {
+		t.Fatalf("DeriveAccounts failed: %v", err)
+	}
+	seed, _ := mnemonicSeed(DefaultMnemonic, "")
+	master, _ := newMasterKey(seed)
+	indexes, _ := parseDerivationPath("m/44'/60'/0'/10")
+	key, err := master.derive(indexes)
+	if err != nil {
+		t.Fatalf("derive failed: %v", err)
+	}
+	if got := crypto.FromECDSA(accounts[0].PrivateKey); !bytes.Equal(got, key.key) {
+		t.Fatalf("account 0 of a path without trailing slash has key %x, expected %x", got, key.key)
+	}
+}
+
+// TestAnvil_HDVectors checks the BIP39 seed and BIP32 derivation against the
+// published test vectors.
+func TestAnvil_HDVectors(t *testing.T) {
+	// Trezor's BIP39 reference vector with passphrase "TREZOR".
+	seed, err := mnemonicSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "TREZOR")
+	if err != nil {
+		t.Fatalf("mnemonicSeed failed: %v", err)
+	}
+	if want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"; common.Bytes2Hex(seed) != want {
+		t.Fatalf("seed is %x, expected %s", seed, want)
+	}
+
+	// BIP32 test vector 1, with key and chain code decoded from the xprv strings.
+	master, err := newMasterKey(common.FromHex("000102030405060708090a0b0c0d0e0f"))
+	if err != nil {
+		t.Fatalf("newMasterKey failed: %v", err)
+	}
+	for _, v := range []struct {
+		path, key, chainCode string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e"},
+	} {
+		indexes, err := parseDerivationPath(v.path)
+		if err != nil {
+			t.Fatalf("parseDerivationPath(%q) failed: %v", v.path, err)
+		}
+		key, err := master.derive(indexes)
+		if err != nil {
+			t.Fatalf("derive(%q) failed: %v", v.path, err)
+		}
+		if common.Bytes2Hex(key.key) != v.key || common.Bytes2Hex(key.chainCode) != v.chainCode {
+			t.Fatalf("%s derived key %x and chain code %x", v.path, key.key, key.chainCode)
+		}
+	}
+}
+
+// TestAnvil_TransactOpts checks that TransactOpts signs with the node's chain ID.
+func TestAnvil_TransactOpts(t *testing.T) {
+	anvl := stubAnvil(t, func(method string, params []json.RawMessage) any {
+		return "0x7a69"
+	})
+	anvl.accounts, _ = DeriveAccounts(DefaultMnemonic, "", 1)
+
+	opts, err := anvl.TransactOpts(0)
+	if err != nil {
+		t.Fatalf("TransactOpts failed: %v", err)
+	}
+	if opts.From != account0 {
+		t.Fatalf("TransactOpts signs as %s, expected %s", opts.From, account0)
+	}
+
+	if _, err := anvl.TransactOpts(1); err == nil {
+		t.Fatalf("TransactOpts succeeded for a missing account")
+	}
+}
@@ -6,12 +6,14 @@ package anvil
 import (
 	"context"
 	"fmt"
+	"io"
+	neturl "net/url"
 	"os"
 	"os/exec"
 	"strings"
-	"syscall"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -19,9 +21,23 @@ import (
 type Anvil struct {
 	url       string
 	wsUrl     string
-	cmd       *exec.Cmd
+	proc      *process
+	logs      *logSink
+	version   Version
 	rpcClient *rpc.Client
 	ethClient *ethclient.Client
+	accounts  []DevAccount
+	report    *StartupReport
+
+	shutdownTimeout time.Duration
+}
+
+// startupTimeout returns how long starting an instance with config may take.
+func startupTimeout(config *Config) time.Duration {
+	if config.startupTimeout != 0 {
+		return config.startupTimeout
+	}
+	return defaultStartupTimeout
 }
 
 // New creates a new Anvil instance with default configuration.
@@ -31,68 +47,276 @@ func New() (Anvil, error) {
 }
 
 // NewWithConfig creates a new Anvil instance with custom configuration.
+// The configuration is checked with Config.Validate before Anvil is started.
 func NewWithConfig(config *Config) (Anvil, error) {
+	if err := config.Validate(); err != nil {
+		return Anvil{}, err
+	}
+
+	timeout := startupTimeout(config)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	binary, err := FindBinary(config)
+	if err != nil {
+		return Anvil{}, err
+	}
+
+	accounts, err := configAccounts(config)
+	if err != nil {
+		return Anvil{}, err
+	}
+
 	args := getArgs(config)
 
-	cmd := exec.Command("anvil", args...)
+	var warnings io.Writer = os.Stderr
+	if config.logWriter != nil {
+		warnings = config.logWriter
+	}
+	version, err := startupVersion(ctx, binary, args, flagVersions, config.versionCheck, warnings)
+	if err != nil {
+		return Anvil{}, err
+	}
+
+	// Without an explicit port, Anvil binds an ephemeral port so several
+	// instances can run side by side. The actual port is read back from the
+	// "Listening on" line of its startup output, which is suppressed in quiet
+	// mode, so a free port is picked up front in that case instead.
+	port := config.port
+	if port == 0 && config.quiet {
+		p, err := freePort()
+		if err != nil {
+			return Anvil{}, err
+		}
+		port = p
+	}
+	if config.port == 0 {
+		args = append(args, "--port", fmt.Sprint(port))
+	}
+
+	// The startup report is read back from the --config-out file once Anvil
+	// answers requests. Without a configured path, a temporary file is used.
+	reportPath := config.configOut
+	if config.startupReport && reportPath == "" {
+		f, err := os.CreateTemp("", "anvil-config-*.json")
+		if err != nil {
+			return Anvil{}, fmt.Errorf("error creating startup report file: %w", err)
+		}
+		f.Close()
+		reportPath = f.Name()
+		defer os.Remove(reportPath)
+		args = append(args, "--config-out", reportPath)
+	} else if config.startupReport {
+		// Anvil overwrites the file anyway; removing it first keeps a report
+		// left over from an earlier run from being read as this one's.
+		os.Remove(reportPath)
+	}
+
+	// Recent output is kept in memory so it can be inspected with Logs, and
+	// so startup failures can report why the process exited.
+	bufferSize := defaultLogBufferSize
+	if config.logBufferSize > 0 {
+		bufferSize = config.logBufferSize
+	}
+	logs := newLogSink(bufferSize, config.logWriter, config.jsonLogs)
+
+	listenAddr := make(chan uint, 1)
+	stdout := newLineWriter(func(line string) {
+		logs.write(false, line)
+		if p, ok := parseListenPort(line); ok {
+			select {
+			case listenAddr <- p:
+			default:
+			}
+		}
+	})
+	stderr := newLineWriter(func(line string) {
+		logs.write(true, line)
+	})
+
+	cmd := exec.Command(binary, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	if config.showLogs {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		cmd.Stdout = io.MultiWriter(os.Stdout, stdout)
+		cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
 	}
 
-	err := cmd.Start()
+	proc, err := startProcess(cmd)
 	if err != nil {
 		return Anvil{}, fmt.Errorf("Failed to start Anvil: %v", err)
 	}
 
-	var port uint = 8545 // Default port
-	if config.port != 0 {
-		port = config.port
+	if port == 0 {
+		select {
+		case port = <-listenAddr:
+		case <-proc.done:
+			return Anvil{}, newStartError(proc, logs)
+		case <-ctx.Done():
+			proc.kill()
+			return Anvil{}, fmt.Errorf("Anvil did not report a listening address within %s", timeout)
+		}
 	}
 
 	url := fmt.Sprintf("http://localhost:%d", port)
 	wsUrl := fmt.Sprintf("ws://localhost:%d", port)
 
+	// All requests, including the custom anvil_/evm_ methods, share a single
+	// client so connections are reused across calls.
 	rpcUrl := url
-	if strings.HasPrefix(config.forkURL, "ws") {
+	if config.ipc {
+		rpcUrl = defaultIPCPath
+		if config.ipcPath != "" {
+			rpcUrl = config.ipcPath
+		}
+	} else if strings.HasPrefix(config.forkURL, "ws") {
 		rpcUrl = wsUrl
 	}
 
-	rpcClient, err := rpc.Dial(rpcUrl)
+	rpcClient, err := waitReady(ctx, proc, rpcUrl)
 	if err != nil {
-		return Anvil{}, fmt.Errorf("error connecting to RPC: %v", err)
+		select {
+		case <-proc.done:
+			return Anvil{}, newStartError(proc, logs)
+		default:
+		}
+		proc.kill()
+		return Anvil{}, fmt.Errorf("Anvil did not start within %s: %w", timeout, err)
 	}
 
-	ethClient := ethclient.NewClient(rpcClient)
-
-	// Wait until client is ready
-	startedSuccessfully := false
-	for range 1000 {
-		_, err := ethClient.BlockNumber(
-			context.Background(),
-		)
+	var report *StartupReport
+	if config.startupReport {
+		report, err = waitStartupReport(ctx, proc, reportPath)
 		if err != nil {
-			time.Sleep(100 * time.Millisecond)
-			continue
+			rpcClient.Close()
+			select {
+			case <-proc.done:
+				return Anvil{}, newStartError(proc, logs)
+			default:
+			}
+			proc.kill()
+			return Anvil{}, fmt.Errorf("Anvil did not write its startup report within %s: %w", timeout, err)
 		}
-
-		startedSuccessfully = true
-		break
+		accounts = report.accounts()
 	}
 
-	if !startedSuccessfully {
-		return Anvil{}, fmt.Errorf("Anvil did not start in time")
+	ethClient := ethclient.NewClient(rpcClient)
+
+	shutdownTimeout := defaultShutdownTimeout
+	if config.shutdownTimeout != 0 {
+		shutdownTimeout = config.shutdownTimeout
 	}
 
 	return Anvil{
 		url:       url,
 		wsUrl:     wsUrl,
-		cmd:       cmd,
+		proc:      proc,
+		logs:      logs,
+		version:   version,
 		rpcClient: rpcClient,
 		ethClient: ethClient,
+		accounts:  accounts,
+		report:    report,
+
+		shutdownTimeout: shutdownTimeout,
+	}, nil
+}
+
+// Connect attaches to an already running Anvil node, e.g. one started with
+// docker-compose or in a separate terminal, without spawning a process.
+// rawUrl may be an HTTP, WebSocket or IPC endpoint. The returned handle
+// supports the same methods as one created with NewWithConfig, but Close
+// only closes the client connections and leaves the node running.
+func Connect(rawUrl string) (Anvil, error) {
+	return ConnectContext(context.Background(), rawUrl)
+}
+
+// ConnectContext is like Connect but honors ctx while dialing and checking
+// that the node is reachable.
+func ConnectContext(ctx context.Context, rawUrl string) (Anvil, error) {
+	rpcClient, err := rpc.DialContext(ctx, rawUrl)
+	if err != nil {
+		return Anvil{}, fmt.Errorf("error connecting to RPC: %v", err)
+	}
+
+	var blockNumber hexutil.Uint64
+	err = rpcClient.CallContext(ctx, &blockNumber, "eth_blockNumber")
+	if err != nil {
+		rpcClient.Close()
+		return Anvil{}, fmt.Errorf("error connecting to Anvil at %s: %w", rawUrl, err)
+	}
+
+	url, wsUrl := endpointUrls(rawUrl)
+
+	accounts, err := DeriveAccounts(DefaultMnemonic, "", defaultAccounts)
+	if err != nil {
+		rpcClient.Close()
+		return Anvil{}, err
+	}
+
+	return Anvil{
+		url:       url,
+		wsUrl:     wsUrl,
+		rpcClient: rpcClient,
+		ethClient: ethclient.NewClient(rpcClient),
+		accounts:  accounts,
 	}, nil
 }
 
+// endpointUrls derives the HTTP and WebSocket URLs of a node from the URL
+// used to reach it. Anvil serves both on the same port. IPC endpoints have
+// neither, so both are returned empty.
+func endpointUrls(rawUrl string) (string, string) {
+	u, err := neturl.Parse(rawUrl)
+	if err != nil {
+		return "", ""
+	}
+
+	httpUrl, wsUrl := *u, *u
+	switch u.Scheme {
+	case "http", "ws":
+		httpUrl.Scheme, wsUrl.Scheme = "http", "ws"
+	case "https", "wss":
+		httpUrl.Scheme, wsUrl.Scheme = "https", "wss"
+	default:
+		return "", ""
+	}
+
+	return httpUrl.String(), wsUrl.String()
+}
+
+// waitReady dials rpcUrl and polls the node until it answers requests, the
+// process exits, or ctx expires. WebSocket and IPC connections are
+// established eagerly, so dialing is retried along with the first request.
+func waitReady(ctx context.Context, proc *process, rpcUrl string) (*rpc.Client, error) {
+	var rpcClient *rpc.Client
+	for {
+		var err error
+		if rpcClient == nil {
+			rpcClient, err = rpc.DialContext(ctx, rpcUrl)
+		}
+		if err == nil {
+			var blockNumber hexutil.Uint64
+			err = rpcClient.CallContext(ctx, &blockNumber, "eth_blockNumber")
+			if err == nil {
+				return rpcClient, nil
+			}
+		}
+
+		select {
+		case <-proc.done:
+		case <-ctx.Done():
+		case <-time.After(100 * time.Millisecond):
+			continue
+		}
+
+		if rpcClient != nil {
+			rpcClient.Close()
+		}
+		return nil, err
+	}
+}
+
 // EthClient returns an instance of *ethclient.Client
 // connected to the running Anvil instance
 func (a *Anvil) EthClient() *ethclient.Client {
@@ -109,15 +333,47 @@ func (a *Anvil) WsUrl() string {
 	return a.wsUrl
 }
 
-// Close closes the running Anvil instance
+// Close closes the running Anvil instance. For handles created with Connect,
+// only the client connections are closed and the node is left running.
+// Otherwise it asks Anvil to shut down
+// gracefully and waits for the process to exit, so any state configured with
+// SetDumpStatePath or SetStatePath has been fully written when Close returns.
+// If Anvil does not exit within the shutdown timeout it is killed.
+//
+// Close is safe to call multiple times; later calls return the result of the first.
 func (a *Anvil) Close() error {
-	err := a.cmd.Process.Signal(syscall.SIGTERM)
-	if err != nil {
-		return fmt.Errorf("error closing Anvil: %v", err)
-	}
-
 	a.rpcClient.Close()
 	a.ethClient.Close()
 
-	return nil
+	if a.proc == nil {
+		return nil
+	}
+	return a.proc.stop(a.shutdownTimeout)
+}
+
+// Version returns the version reported by `anvil --version` at startup.
+// It is the zero Version if the check was disabled with VersionCheckOff, if
+// no configured flag needed it, or if the handle was created with Connect.
+func (a *Anvil) Version() Version {
+	return a.version
+}
+
+// Logs returns up to n of the most recent lines written by Anvil to stdout and
+// stderr, oldest first. If n <= 0, every buffered line is returned.
+// Handles created with Connect have no access to the node's output and return nil.
+// When SetJSONLogs is enabled, each line also carries its parsed LogRecord.
+func (a *Anvil) Logs(n int) []LogLine {
+	if a.logs == nil {
+		return nil
+	}
+	return a.logs.ring.last(n)
+}
+
+// ProcessState returns the exit state of the Anvil process once it has
+// exited, or nil while it is still running or if the handle was created with Connect.
+func (a *Anvil) ProcessState() *os.ProcessState {
+	if a.proc == nil {
+		return nil
+	}
+	return a.proc.state()
 }
@@ -0,0 +1,88 @@
+package anvil
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAnvilBinary writes a shell script standing in for anvil and returns its path.
+// The script reports version 1.3.0 when run with --version.
+func fakeAnvilBinary(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "anvil")
+	header := "#!/bin/sh\nif [ \"$1\" = \"--version\" ]; then echo 'anvil Version: 1.3.0-stable'; exit 0; fi\n"
+	if err := os.WriteFile(path, []byte(header+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestAnvil_StartupCrash checks that a process exiting during startup fails fast with its stderr.
+func TestAnvil_StartupCrash(t *testing.T) {
+	binary := fakeAnvilBinary(t, "echo 'error: unexpected argument' >&2\nexit 2\n")
+
+	start := time.Now()
+	_, err := NewWithConfig(NewConfig().SetBinaryPath(binary).SetStartupTimeout(30 * time.Second))
+	if err == nil {
+		t.Fatalf("NewWithConfig succeeded with a crashing binary")
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatalf("startup failure took %s to detect", time.Since(start))
+	}
+
+	var startErr *StartError
+	if !errors.As(err, &startErr) {
+		t.Fatalf("expected *StartError, got %T: %v", err, err)
+	}
+	if startErr.ExitCode != 2 || !strings.Contains(startErr.Stderr, "unexpected argument") {
+		t.Fatalf("unexpected start error: %+v", startErr)
+	}
+}
+
+// TestAnvil_StartupTimeout checks that a process that never becomes ready is killed after the startup timeout.
+func TestAnvil_StartupTimeout(t *testing.T) {
+	binary := fakeAnvilBinary(t, "exec sleep 60\n")
+
+	_, err := NewWithConfig(NewConfig().SetBinaryPath(binary).SetStartupTimeout(500 * time.Millisecond))
+	if err == nil {
+		t.Fatalf("NewWithConfig succeeded with a binary that never listens")
+	}
+
+	var startErr *StartError
+	if errors.As(err, &startErr) {
+		t.Fatalf("timeout reported as a start error: %v", err)
+	}
+}
+
+// TestAnvil_Connect checks attaching to an existing node and that Close leaves it running.
+func TestAnvil_Connect(t *testing.T) {
+	var methods []string
+	srv := newStubServer(t, func(method string, params []json.RawMessage) any {
+		methods = append(methods, method)
+		return "0x1"
+	})
+
+	anvl, err := Connect(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if anvl.HttpUrl() != srv.URL || anvl.WsUrl() != "ws"+strings.TrimPrefix(srv.URL, "http") {
+		t.Fatalf("unexpected urls: %s %s", anvl.HttpUrl(), anvl.WsUrl())
+	}
+
+	if _, err := anvl.EvmSnapshot(); err != nil {
+		t.Fatalf("EvmSnapshot failed: %v", err)
+	}
+	if err := anvl.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(methods) != 2 || methods[0] != "eth_blockNumber" || methods[1] != "evm_snapshot" {
+		t.Fatalf("unexpected calls: %v", methods)
+	}
+}
@@ -0,0 +1,100 @@
+// Package anviltest provides helpers for using Anvil in Go tests.
+package anviltest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/banky/eth-utils/anvil"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// New starts an Anvil instance configured with cfg for the duration of the
+// test. A nil cfg uses anvil.NewConfig(). The instance is closed when the test
+// finishes, and if the test failed, Anvil's output is written to the test log.
+//
+// If no anvil binary is installed, the test is skipped instead of failing.
+func New(tb testing.TB, cfg *anvil.Config) *anvil.Anvil {
+	tb.Helper()
+
+	if cfg == nil {
+		cfg = anvil.NewConfig()
+	}
+	if _, err := anvil.FindBinary(cfg); errors.Is(err, anvil.ErrBinaryNotFound) {
+		tb.Skipf("skipping: %v", err)
+	}
+
+	a, err := anvil.NewWithConfig(cfg)
+	if err != nil {
+		tb.Fatalf("starting anvil failed: %v", err)
+	}
+
+	tb.Cleanup(func() {
+		if tb.Failed() {
+			for _, line := range a.Logs(0) {
+				tb.Log(line.Text)
+			}
+		}
+		if err := a.Close(); err != nil {
+			tb.Errorf("closing anvil failed: %v", err)
+		}
+	})
+
+	return &a
+}
+
+// AssertBalance checks that the balance of address is want.
+func AssertBalance(tb testing.TB, a *anvil.Anvil, address common.Address, want *big.Int) {
+	tb.Helper()
+
+	got, err := a.EthClient().BalanceAt(context.Background(), address, nil)
+	if err != nil {
+		tb.Fatalf("reading balance of %s failed: %v", address, err)
+	}
+	if got.Cmp(want) != 0 {
+		tb.Errorf("balance of %s is %s, want %s", address, got, want)
+	}
+}
+
+// AssertNonce checks that the nonce of address is want.
+func AssertNonce(tb testing.TB, a *anvil.Anvil, address common.Address, want uint64) {
+	tb.Helper()
+
+	got, err := a.EthClient().NonceAt(context.Background(), address, nil)
+	if err != nil {
+		tb.Fatalf("reading nonce of %s failed: %v", address, err)
+	}
+	if got != want {
+		tb.Errorf("nonce of %s is %d, want %d", address, got, want)
+	}
+}
+
+// AssertCode checks that the code deployed at address is want. An empty want
+// checks that address has no code.
+func AssertCode(tb testing.TB, a *anvil.Anvil, address common.Address, want []byte) {
+	tb.Helper()
+
+	got, err := a.EthClient().CodeAt(context.Background(), address, nil)
+	if err != nil {
+		tb.Fatalf("reading code of %s failed: %v", address, err)
+	}
+	if !bytes.Equal(got, want) {
+		tb.Errorf("code of %s is %#x, want %#x", address, got, want)
+	}
+}
+
+// AssertStorage checks that storage slot of address holds want.
+func AssertStorage(tb testing.TB, a *anvil.Anvil, address common.Address, slot, want common.Hash) {
+	tb.Helper()
+
+	raw, err := a.EthClient().StorageAt(context.Background(), address, slot, nil)
+	if err != nil {
+		tb.Fatalf("reading storage slot %s of %s failed: %v", slot, address, err)
+	}
+	if got := common.BytesToHash(raw); got != want {
+		tb.Errorf("storage slot %s of %s is %s, want %s", slot, address, got, want)
+	}
+}
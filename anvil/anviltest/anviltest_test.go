@@ -0,0 +1,71 @@
+package anviltest
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/banky/eth-utils/anvil"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// recordingTB captures Errorf calls instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// connectStub connects to a fake node serving fixed account state.
+func connectStub(t *testing.T) *anvil.Anvil {
+	fake := NewFake(t)
+	fake.SetResult("eth_getBalance", "0xde0b6b3a7640000")
+	fake.SetResult("eth_getTransactionCount", "0x2a")
+	fake.SetResult("eth_getCode", "0x6001")
+	fake.SetResult("eth_getStorageAt", "0x0000000000000000000000000000000000000000000000000000000000000007")
+	return fake.Anvil(t)
+}
+
+// TestAssertions checks that the assertion helpers pass on matching state and report mismatches.
+func TestAssertions(t *testing.T) {
+	a := connectStub(t)
+	addr := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	AssertBalance(t, a, addr, big.NewInt(1e18))
+	AssertNonce(t, a, addr, 42)
+	AssertCode(t, a, addr, []byte{0x60, 0x01})
+	AssertStorage(t, a, addr, common.Hash{}, common.HexToHash("0x07"))
+
+	rec := &recordingTB{TB: t}
+	AssertBalance(rec, a, addr, big.NewInt(1))
+	AssertNonce(rec, a, addr, 1)
+	AssertCode(rec, a, addr, nil)
+	AssertStorage(rec, a, addr, common.Hash{}, common.Hash{})
+	if len(rec.errors) != 4 {
+		t.Fatalf("expected 4 mismatches, got %q", rec.errors)
+	}
+}
+
+// TestNew_MissingBinary checks that New skips the test when anvil is not installed.
+func TestNew_MissingBinary(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		defer func() {
+			if !t.Skipped() {
+				t.Errorf("New did not skip the test")
+			}
+		}()
+		New(t, anvil.NewConfig().SetBinaryPath(t.TempDir()+"/anvil"))
+	})
+}
+
+// TestNew starts a node and closes it when the test ends.
+func TestNew(t *testing.T) {
+	a := New(t, nil)
+
+	AssertNonce(t, a, a.Accounts()[0].Address, 0)
+}
@@ -0,0 +1,465 @@
+package anviltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/banky/eth-utils/anvil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Handler answers a JSON-RPC request to a Fake. Returning an *anvil.RPCError
+// sends its code, message and data as the JSON-RPC error object; any other
+// error is sent as an internal error with the error text as message.
+type Handler func(params []json.RawMessage) (any, error)
+
+// Call is a request received by a Fake.
+type Call struct {
+	Method string
+	Params []json.RawMessage
+}
+
+// Fake is an in-process stand-in for an Anvil node, for unit tests that
+// should not depend on foundry being installed. It answers every method the
+// anvil package wraps with a plausible default result and records every call.
+// Responses can be overridden per method with Handle, SetResult and SetError.
+//
+// Fake does not execute transactions. The state it keeps is a block counter,
+// which anvil_mine and evm_mine advance, and contract storage, which
+// anvil_setStorageAt writes and eth_getStorageAt reads. evm_snapshot and
+// evm_revert save and restore both, and anvil_reset clears them.
+//
+// Every address behaves like an OpenZeppelin ERC20 token for eth_call:
+// balanceOf reads the balance mapping at slot 0 and totalSupply reads slot 2,
+// honoring stateDiff overrides. anvil_dealERC20 writes the balance mapping,
+// so DealERC20 works offline. Other calls return empty data.
+//
+// Requests for methods without a handler fail with "method not found".
+type Fake struct {
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	handlers  map[string]Handler
+	calls     []Call
+	block     uint64
+	storage   map[common.Address]map[common.Hash]common.Hash
+	snapshots []fakeSnapshot
+	nextID    int
+}
+
+// fakeSnapshot is the state saved by evm_snapshot.
+type fakeSnapshot struct {
+	id      string
+	block   uint64
+	storage map[common.Address]map[common.Hash]common.Hash
+}
+
+// Storage layout of the ERC20 tokens emulated by eth_call.
+var (
+	balancesSlot    = anvil.Slot(0)
+	totalSupplySlot = anvil.Slot(2)
+
+	balanceOfSelector   = common.FromHex("0x70a08231")
+	totalSupplySelector = common.FromHex("0x18160ddd")
+)
+
+// NewFake starts a Fake that is shut down when the test finishes.
+func NewFake(tb testing.TB) *Fake {
+	tb.Helper()
+
+	f := &Fake{handlers: map[string]Handler{}, storage: map[common.Address]map[common.Hash]common.Hash{}}
+	for method, result := range defaultResults {
+		f.SetResult(method, result)
+	}
+	f.handlers["eth_blockNumber"] = f.blockNumber
+	f.handlers["anvil_mine"] = f.mine
+	f.handlers["evm_mine"] = f.mineOne
+	f.handlers["evm_snapshot"] = f.snapshot
+	f.handlers["evm_revert"] = f.revert
+	f.handlers["anvil_reset"] = f.reset
+	f.handlers["anvil_setStorageAt"] = f.setStorageAt
+	f.handlers["eth_getStorageAt"] = f.getStorageAt
+	f.handlers["eth_call"] = f.call
+	f.handlers["anvil_dealERC20"] = f.dealERC20
+	f.handlers["eth_sendUnsignedTransaction"] = f.sendTransaction
+
+	f.srv = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	tb.Cleanup(f.srv.Close)
+	return f
+}
+
+// URL returns the HTTP endpoint of the fake node.
+func (f *Fake) URL() string {
+	return f.srv.URL
+}
+
+// Anvil connects to the fake node with anvil.Connect. The connection is
+// closed when the test finishes.
+func (f *Fake) Anvil(tb testing.TB) *anvil.Anvil {
+	tb.Helper()
+
+	a, err := anvil.Connect(f.URL())
+	if err != nil {
+		tb.Fatalf("connecting to fake anvil failed: %v", err)
+	}
+	tb.Cleanup(func() { a.Close() })
+	return &a
+}
+
+// Handle sets the handler for method, replacing the default.
+func (f *Fake) Handle(method string, h Handler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = h
+}
+
+// SetResult makes method always return result.
+func (f *Fake) SetResult(method string, result any) {
+	f.Handle(method, func([]json.RawMessage) (any, error) {
+		return result, nil
+	})
+}
+
+// SetError makes method always fail with the given JSON-RPC error.
+func (f *Fake) SetError(method string, code int, message string) {
+	f.Handle(method, func([]json.RawMessage) (any, error) {
+		return nil, &anvil.RPCError{Code: code, Message: message}
+	})
+}
+
+// Calls returns the calls received for method in order, or all calls if
+// method is empty.
+func (f *Fake) Calls(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if method == "" {
+		return slices.Clone(f.calls)
+	}
+	var calls []Call
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded calls.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+func (f *Fake) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, Call{Method: req.Method, Params: req.Params})
+	h, ok := f.handlers[req.Method]
+	f.mu.Unlock()
+
+	res := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+	if !ok {
+		res["error"] = &anvil.RPCError{Code: -32601, Message: fmt.Sprintf("the method %s does not exist/is not available", req.Method)}
+	} else if result, err := h(req.Params); err != nil {
+		rpcErr, ok := err.(*anvil.RPCError)
+		if !ok {
+			rpcErr = &anvil.RPCError{Code: -32603, Message: err.Error()}
+		}
+		res["error"] = rpcErr
+	} else {
+		res["result"] = result
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (f *Fake) blockNumber([]json.RawMessage) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fmt.Sprintf("0x%x", f.block), nil
+}
+
+// mine handles anvil_mine, advancing the block counter by the requested
+// number of blocks, or by one.
+func (f *Fake) mine(params []json.RawMessage) (any, error) {
+	blocks := uint64(1)
+	var hex string
+	if len(params) > 0 && json.Unmarshal(params[0], &hex) == nil {
+		if _, err := fmt.Sscanf(hex, "0x%x", &blocks); err != nil {
+			return nil, fmt.Errorf("invalid block count %s", params[0])
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.block += blocks
+	return nil, nil
+}
+
+// mineOne handles evm_mine, whose optional parameter is a timestamp.
+func (f *Fake) mineOne([]json.RawMessage) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.block++
+	return "0x0", nil
+}
+
+func (f *Fake) snapshot([]json.RawMessage) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	id := fmt.Sprintf("0x%x", f.nextID)
+	f.snapshots = append(f.snapshots, fakeSnapshot{id: id, block: f.block, storage: cloneStorage(f.storage)})
+	return id, nil
+}
+
+// revert restores the snapshot and drops it and every later one, as Anvil does.
+func (f *Fake) revert(params []json.RawMessage) (any, error) {
+	var id string
+	if len(params) > 0 {
+		json.Unmarshal(params[0], &id)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := slices.IndexFunc(f.snapshots, func(s fakeSnapshot) bool { return s.id == id })
+	if i < 0 {
+		return false, nil
+	}
+	f.block = f.snapshots[i].block
+	f.storage = f.snapshots[i].storage
+	f.snapshots = f.snapshots[:i]
+	return true, nil
+}
+
+// reset handles anvil_reset, returning to an empty chain at block 0.
+func (f *Fake) reset([]json.RawMessage) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.block = 0
+	f.storage = map[common.Address]map[common.Hash]common.Hash{}
+	f.snapshots = nil
+	return nil, nil
+}
+
+func (f *Fake) setStorageAt(params []json.RawMessage) (any, error) {
+	var (
+		address     common.Address
+		slot, value common.Hash
+	)
+	if err := decodeParams(params, &address, &slot, &value); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.store(address, slot, value)
+	return true, nil
+}
+
+func (f *Fake) getStorageAt(params []json.RawMessage) (any, error) {
+	var (
+		address common.Address
+		slot    common.Hash
+	)
+	if err := decodeParams(params, &address, &slot); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.storage[address][slot], nil
+}
+
+// call handles eth_call by emulating balanceOf and totalSupply of an ERC20
+// token at the called address.
+func (f *Fake) call(params []json.RawMessage) (any, error) {
+	var msg struct {
+		To    common.Address `json:"to"`
+		Input hexutil.Bytes  `json:"input"`
+		Data  hexutil.Bytes  `json:"data"`
+	}
+	if err := decodeParams(params, &msg); err != nil {
+		return nil, err
+	}
+	input := msg.Input
+	if input == nil {
+		input = msg.Data
+	}
+
+	var overrides map[common.Address]struct {
+		StateDiff map[common.Hash]common.Hash `json:"stateDiff"`
+	}
+	if len(params) > 2 {
+		if err := json.Unmarshal(params[2], &overrides); err != nil {
+			return nil, &anvil.RPCError{Code: -32602, Message: fmt.Sprintf("invalid state override: %v", err)}
+		}
+	}
+
+	var slot common.Hash
+	switch {
+	case len(input) == 36 && bytes.Equal(input[:4], balanceOfSelector):
+		slot = anvil.MappingSlot(balancesSlot, common.BytesToHash(input[4:]))
+	case len(input) == 4 && bytes.Equal(input, totalSupplySelector):
+		slot = totalSupplySlot
+	default:
+		return hexutil.Bytes{}, nil
+	}
+
+	if value, ok := overrides[msg.To].StateDiff[slot]; ok {
+		return value, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.storage[msg.To][slot], nil
+}
+
+// dealERC20 handles anvil_dealERC20 by writing the balance mapping that
+// eth_call reads. Like Anvil, it leaves the total supply unchanged.
+func (f *Fake) dealERC20(params []json.RawMessage) (any, error) {
+	var (
+		holder, token common.Address
+		amount        hexutil.Big
+	)
+	if err := decodeParams(params, &holder, &token, &amount); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.store(token, anvil.MappingSlot(balancesSlot, common.BytesToHash(holder.Bytes())), common.BigToHash(amount.ToInt()))
+	return nil, nil
+}
+
+// store writes a storage slot. f.mu must be held.
+func (f *Fake) store(address common.Address, slot, value common.Hash) {
+	if f.storage[address] == nil {
+		f.storage[address] = map[common.Hash]common.Hash{}
+	}
+	f.storage[address][slot] = value
+}
+
+// cloneStorage returns a deep copy of storage.
+func cloneStorage(storage map[common.Address]map[common.Hash]common.Hash) map[common.Address]map[common.Hash]common.Hash {
+	clone := make(map[common.Address]map[common.Hash]common.Hash, len(storage))
+	for address, slots := range storage {
+		clone[address] = maps.Clone(slots)
+	}
+	return clone
+}
+
+// decodeParams decodes the leading params into out, failing with an
+// invalid params error as Anvil does.
+func decodeParams(params []json.RawMessage, out ...any) error {
+	if len(params) < len(out) {
+		return &anvil.RPCError{Code: -32602, Message: fmt.Sprintf("expected %d params, got %d", len(out), len(params))}
+	}
+	for i, v := range out {
+		if err := json.Unmarshal(params[i], v); err != nil {
+			return &anvil.RPCError{Code: -32602, Message: fmt.Sprintf("invalid param %d: %v", i, err)}
+		}
+	}
+	return nil
+}
+
+// sendTransaction returns a hash derived from the request.
+func (f *Fake) sendTransaction(params []json.RawMessage) (any, error) {
+	var data []byte
+	for _, p := range params {
+		data = append(data, p...)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	return crypto.Keccak256Hash(data, []byte(fmt.Sprint(f.nextID))), nil
+}
+
+// emptyPool is the txpool_content and txpool_inspect result of an empty pool.
+var emptyPool = map[string]any{"pending": map[string]any{}, "queued": map[string]any{}}
+
+// defaultResults are the fixed results of the methods without stateful handlers.
+var defaultResults = map[string]any{
+	"eth_chainId":             "0x7a69",
+	"net_version":             "31337",
+	"eth_getBalance":          "0x0",
+	"eth_getCode":             "0x",
+	"eth_getTransactionCount": "0x0",
+
+	"anvil_addBalance":                   nil,
+	"anvil_autoImpersonateAccount":       nil,
+	"anvil_dropAllTransactions":          nil,
+	"anvil_dropTransaction":              nil,
+	"anvil_dumpState":                    "0x",
+	"anvil_enableTraces":                 nil,
+	"anvil_getAutomine":                  true,
+	"anvil_getBlobByHash":                "0x",
+	"anvil_getBlobSidecarsByBlockId":     []any{},
+	"anvil_getBlobsByBlockId":            []any{},
+	"anvil_getBlobsByTransactionHash":    []any{},
+	"anvil_getIntervalMining":            nil,
+	"anvil_impersonateAccount":           nil,
+	"anvil_impersonateSignature":         nil,
+	"anvil_loadState":                    true,
+	"anvil_removeBlockTimestampInterval": true,
+	"anvil_removePoolTransactions":       nil,
+	"anvil_reorg":                        nil,
+	"anvil_rollback":                     nil,
+	"anvil_setBalance":                   nil,
+	"anvil_setBlockTimestampInterval":    nil,
+	"anvil_setChainId":                   nil,
+	"anvil_setCode":                      nil,
+	"anvil_setCoinbase":                  nil,
+	"anvil_setERC20Allowance":            nil,
+	"anvil_setLoggingEnabled":            nil,
+	"anvil_setMinGasPrice":               nil,
+	"anvil_setNextBlockBaseFeePerGas":    nil,
+	"anvil_setNonce":                     nil,
+	"anvil_setRpcUrl":                    nil,
+	"anvil_setTime":                      0,
+	"anvil_stopImpersonatingAccount":     nil,
+	"anvil_nodeInfo": map[string]any{
+		"currentBlockNumber": 0,
+		"hardFork":           "prague",
+		"transactionOrder":   "fees",
+		"environment":        map[string]any{"baseFee": 1_000_000_000, "chainId": 31337, "gasLimit": 30_000_000, "gasPrice": 2_000_000_000},
+		"forkConfig":         map[string]any{},
+	},
+	"anvil_metadata": map[string]any{
+		"clientVersion":     "anvil/fake",
+		"chainId":           31337,
+		"latestBlockNumber": 0,
+		"snapshots":         map[string]any{},
+	},
+
+	"evm_increaseTime":          0,
+	"evm_mine_detailed":         []any{},
+	"evm_setAutomine":           nil,
+	"evm_setBlockGasLimit":      true,
+	"evm_setIntervalMining":     nil,
+	"evm_setNextBlockTimestamp": nil,
+
+	"txpool_content": emptyPool,
+	"txpool_inspect": emptyPool,
+	"txpool_status":  map[string]any{"pending": "0x0", "queued": "0x0"},
+}
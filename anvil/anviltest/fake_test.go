@@ -0,0 +1,155 @@
+package anviltest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/banky/eth-utils/anvil"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestFake_Defaults checks that the wrapped methods work against the default handlers.
+func TestFake_Defaults(t *testing.T) {
+	fake := NewFake(t)
+	a := fake.Anvil(t)
+	addr := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+	if err := a.SetBalance(addr, big.NewInt(1)); err != nil {
+		t.Fatalf("SetBalance failed: %v", err)
+	}
+	if _, err := a.SetStorageAt(addr, common.Hash{}, common.Hash{}); err != nil {
+		t.Fatalf("SetStorageAt failed: %v", err)
+	}
+	if status, err := a.TxpoolStatus(); err != nil || status.Pending != 0 {
+		t.Fatalf("TxpoolStatus = %+v, %v", status, err)
+	}
+	if _, err := a.TxpoolContent(); err != nil {
+		t.Fatalf("TxpoolContent failed: %v", err)
+	}
+	if info, err := a.NodeInfo(); err != nil || info.Environment.ChainID != 31337 {
+		t.Fatalf("NodeInfo = %+v, %v", info, err)
+	}
+	if _, err := a.Metadata(); err != nil {
+		t.Fatalf("Metadata failed: %v", err)
+	}
+
+	if err := a.Mine(big.NewInt(3), nil); err != nil {
+		t.Fatalf("Mine failed: %v", err)
+	}
+	if err := a.EvmMine(); err != nil {
+		t.Fatalf("EvmMine failed: %v", err)
+	}
+	if n, err := a.EthClient().BlockNumber(context.Background()); err != nil || n != 4 {
+		t.Fatalf("BlockNumber = %d, %v, expected 4", n, err)
+	}
+
+	if err := a.WithSnapshot(func() error { return nil }); err != nil {
+		t.Fatalf("WithSnapshot failed: %v", err)
+	}
+
+	calls := fake.Calls("anvil_setBalance")
+	if len(calls) != 1 {
+		t.Fatalf("recorded %d anvil_setBalance calls", len(calls))
+	}
+	var got common.Address
+	json.Unmarshal(calls[0].Params[0], &got)
+	if got != addr {
+		t.Fatalf("anvil_setBalance called for %s", got)
+	}
+}
+
+// TestFake_Programmable checks custom results, errors and unknown methods.
+func TestFake_Programmable(t *testing.T) {
+	fake := NewFake(t)
+	a := fake.Anvil(t)
+
+	fake.SetResult("anvil_getAutomine", false)
+	if auto, err := a.GetAutomine(); err != nil || auto {
+		t.Fatalf("GetAutomine = %v, %v", auto, err)
+	}
+
+	fake.SetError("anvil_setCoinbase", -32602, "invalid params")
+	err := a.SetCoinbase(common.Address{})
+	var rpcErr *anvil.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+		t.Fatalf("SetCoinbase returned %v, expected an RPC error", err)
+	}
+
+	fake.Handle("evm_increaseTime", func(params []json.RawMessage) (any, error) {
+		var seconds int64
+		json.Unmarshal(params[0], &seconds)
+		return seconds * 2, nil
+	})
+	if got, err := a.EvmIncreaseTime(30); err != nil || got != 60 {
+		t.Fatalf("EvmIncreaseTime = %d, %v", got, err)
+	}
+
+	if _, err := a.OTSGetApiLevel(); !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Fatalf("unknown method returned %v", err)
+	}
+
+	fake.Reset()
+	if calls := fake.Calls(""); len(calls) != 0 {
+		t.Fatalf("Reset left %d calls", len(calls))
+	}
+}
+
+// TestFake_State checks storage, ERC20 dealing, snapshots and anvil_reset.
+func TestFake_State(t *testing.T) {
+	fake := NewFake(t)
+	a := fake.Anvil(t)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	holder := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	supplySlot := common.BigToHash(big.NewInt(2))
+
+	if _, err := a.SetStorageAt(token, supplySlot, common.BigToHash(big.NewInt(1000))); err != nil {
+		t.Fatalf("SetStorageAt failed: %v", err)
+	}
+	AssertStorage(t, a, token, supplySlot, common.BigToHash(big.NewInt(1000)))
+
+	id, err := a.EvmSnapshot()
+	if err != nil {
+		t.Fatalf("EvmSnapshot failed: %v", err)
+	}
+
+	if err := a.DealERC20(token, holder, big.NewInt(500), nil); err != nil {
+		t.Fatalf("DealERC20 failed: %v", err)
+	}
+	balanceSlot := anvil.MappingSlot(anvil.Slot(0), common.BytesToHash(holder.Bytes()))
+	AssertStorage(t, a, token, balanceSlot, common.BigToHash(big.NewInt(500)))
+	AssertStorage(t, a, token, supplySlot, common.BigToHash(big.NewInt(1000)))
+
+	// Without anvil_dealERC20, DealERC20 finds the slots by probing.
+	fake.SetError("anvil_dealERC20", -32601, "method not found")
+	if err := a.DealERC20(token, holder, big.NewInt(800), &anvil.DealERC20Options{AdjustTotalSupply: true}); err != nil {
+		t.Fatalf("DealERC20 failed: %v", err)
+	}
+	AssertStorage(t, a, token, balanceSlot, common.BigToHash(big.NewInt(800)))
+	AssertStorage(t, a, token, supplySlot, common.BigToHash(big.NewInt(1300)))
+
+	if ok, err := a.EvmRevert(id); err != nil || !ok {
+		t.Fatalf("EvmRevert = %v, %v", ok, err)
+	}
+	AssertStorage(t, a, token, balanceSlot, common.Hash{})
+	AssertStorage(t, a, token, supplySlot, common.BigToHash(big.NewInt(1000)))
+
+	if err := a.Mine(big.NewInt(5), nil); err != nil {
+		t.Fatalf("Mine failed: %v", err)
+	}
+	if _, err := a.EvmSnapshot(); err != nil {
+		t.Fatalf("EvmSnapshot failed: %v", err)
+	}
+	if err := a.Reset(nil); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if n, err := a.EthClient().BlockNumber(context.Background()); err != nil || n != 0 {
+		t.Fatalf("BlockNumber after reset = %d, %v", n, err)
+	}
+	AssertStorage(t, a, token, supplySlot, common.Hash{})
+	if len(fake.snapshots) != 0 {
+		t.Fatalf("reset left snapshots %v", fake.snapshots)
+	}
+}
@@ -0,0 +1,133 @@
+package anviltest
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/banky/eth-utils/anvil"
+)
+
+// Shared is an Anvil instance shared by all tests of a package, typically
+// started in TestMain:
+//
+//	var node *anviltest.Shared
+//
+//	func TestMain(m *testing.M) {
+//		node = anviltest.StartShared(anvil.NewConfig().SetForkURL(forkURL))
+//		code := m.Run()
+//		node.Close()
+//		os.Exit(code)
+//	}
+//
+// Tests then call node.Use(t) to get a handle whose changes are reverted when
+// the test ends.
+type Shared struct {
+	// lock serializes the top-level tests using the instance.
+	lock  sync.Mutex
+	anvil *anvil.Anvil
+	err   error
+
+	mu sync.Mutex
+	// holders are the tests currently using the instance, outermost first.
+	holders []*holder
+}
+
+// holder is a test using a Shared instance.
+type holder struct {
+	name string
+	// children serializes the subtests of the test that call Use.
+	children sync.Mutex
+}
+
+// StartShared starts an Anvil instance configured with cfg. A nil cfg uses
+// anvil.NewConfig(). Errors are not returned but reported by Use, so every
+// test depending on the instance fails (or is skipped if no anvil binary is
+// installed) with the reason.
+func StartShared(cfg *anvil.Config) *Shared {
+	if cfg == nil {
+		cfg = anvil.NewConfig()
+	}
+
+	a, err := anvil.NewWithConfig(cfg)
+	if err != nil {
+		return &Shared{err: err}
+	}
+	return &Shared{anvil: &a}
+}
+
+// Use returns the shared instance for the duration of the test. It takes a
+// snapshot that is reverted when the test finishes, so state changes never
+// leak into other tests. Tests holding the instance are serialized: a test
+// calling Use, including one marked with t.Parallel, waits until the
+// previous holder has finished and reverted its changes.
+//
+// Subtests of a test holding the instance may call Use too. They take nested
+// snapshots on top of their parent's and are serialized among each other, so
+// each subtest starts from the parent's state. Calling Use again in a test
+// that already holds the instance only takes another snapshot.
+func (s *Shared) Use(tb testing.TB) *anvil.Anvil {
+	tb.Helper()
+
+	if errors.Is(s.err, anvil.ErrBinaryNotFound) {
+		tb.Skipf("skipping: %v", s.err)
+	}
+	if s.err != nil {
+		tb.Fatalf("starting shared anvil failed: %v", s.err)
+	}
+
+	name := tb.Name()
+	s.mu.Lock()
+	parent := s.parent(name)
+	s.mu.Unlock()
+
+	if parent != nil && parent.name == name {
+		Snapshot(tb, s.anvil)
+		return s.anvil
+	}
+
+	lock := &s.lock
+	if parent != nil {
+		lock = &parent.children
+	}
+	lock.Lock()
+
+	h := &holder{name: name}
+	s.mu.Lock()
+	s.holders = append(s.holders, h)
+	s.mu.Unlock()
+
+	// Cleanups run last-in first-out, so the snapshot is reverted before
+	// the next test can take the instance.
+	tb.Cleanup(lock.Unlock)
+	tb.Cleanup(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.holders = slices.DeleteFunc(s.holders, func(other *holder) bool { return other == h })
+	})
+	Snapshot(tb, s.anvil)
+
+	return s.anvil
+}
+
+// parent returns the innermost holder that is the test name itself or one of
+// its parent tests, or nil if there is none.
+func (s *Shared) parent(name string) *holder {
+	for i := len(s.holders) - 1; i >= 0; i-- {
+		h := s.holders[i]
+		if name == h.name || strings.HasPrefix(name, h.name+"/") {
+			return h
+		}
+	}
+	return nil
+}
+
+// Close stops the shared instance.
+func (s *Shared) Close() error {
+	if s.anvil == nil {
+		return nil
+	}
+	return s.anvil.Close()
+}
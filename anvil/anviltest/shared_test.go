@@ -0,0 +1,88 @@
+package anviltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// snapshotDepths records how many snapshots are live whenever a new one is taken.
+func snapshotDepths(fake *Fake) func() []int {
+	var (
+		mu     sync.Mutex
+		depths []int
+	)
+	fake.Handle("evm_snapshot", func(params []json.RawMessage) (any, error) {
+		fake.mu.Lock()
+		depth := len(fake.snapshots)
+		fake.mu.Unlock()
+
+		mu.Lock()
+		depths = append(depths, depth)
+		mu.Unlock()
+		return fake.snapshot(params)
+	})
+	return func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return depths
+	}
+}
+
+// TestShared_Use checks that every test gets its own snapshot and that holders are serialized.
+func TestShared_Use(t *testing.T) {
+	fake := NewFake(t)
+	depths := snapshotDepths(fake)
+	shared := &Shared{anvil: fake.Anvil(t)}
+
+	t.Run("group", func(t *testing.T) {
+		for i := range 4 {
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				t.Parallel()
+				shared.Use(t)
+			})
+		}
+	})
+
+	for _, depth := range depths() {
+		if depth != 0 {
+			t.Fatalf("parallel tests held the shared instance at the same time: %v", depths())
+		}
+	}
+	if len(depths()) != 4 || len(fake.snapshots) != 0 {
+		t.Fatalf("took %d snapshots, %v still live", len(depths()), fake.snapshots)
+	}
+}
+
+// TestShared_UseSubtests checks that subtests of a holder can call Use and
+// get nested snapshots, one subtest at a time.
+func TestShared_UseSubtests(t *testing.T) {
+	fake := NewFake(t)
+	depths := snapshotDepths(fake)
+	shared := &Shared{anvil: fake.Anvil(t)}
+
+	t.Run("parent", func(t *testing.T) {
+		shared.Use(t)
+		t.Run("sequential", func(t *testing.T) {
+			shared.Use(t)
+			t.Run("nested", func(t *testing.T) {
+				shared.Use(t)
+			})
+		})
+		for i := range 4 {
+			t.Run(fmt.Sprint(i), func(t *testing.T) {
+				t.Parallel()
+				shared.Use(t)
+			})
+		}
+	})
+
+	want := []int{0, 1, 2, 1, 1, 1, 1}
+	if fmt.Sprint(depths()) != fmt.Sprint(want) {
+		t.Fatalf("snapshot depths %v, want %v", depths(), want)
+	}
+	if len(fake.snapshots) != 0 || len(shared.holders) != 0 {
+		t.Fatalf("snapshots %v and holders %d left after the tests", fake.snapshots, len(shared.holders))
+	}
+}
@@ -0,0 +1,30 @@
+package anviltest
+
+import (
+	"testing"
+
+	"github.com/banky/eth-utils/anvil"
+)
+
+// Snapshot takes a snapshot of a and registers a cleanup with tb that reverts
+// to it when the test or subtest finishes, including after t.Fatal or a
+// panic. Subtests of a test sharing one node can each call Snapshot to run in
+// isolation, as long as they do not run in parallel. Cleanups run in reverse
+// order, so nested snapshots are reverted innermost first.
+func Snapshot(tb testing.TB, a *anvil.Anvil) {
+	tb.Helper()
+
+	id, err := a.EvmSnapshot()
+	if err != nil {
+		tb.Fatalf("EvmSnapshot failed: %v", err)
+	}
+
+	tb.Cleanup(func() {
+		ok, err := a.EvmRevert(id)
+		if err != nil {
+			tb.Errorf("reverting to snapshot %s failed: %v", id, err)
+		} else if !ok {
+			tb.Errorf("snapshot %s could not be reverted", id)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package anviltest
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// TestSnapshot checks that Snapshot reverts when the subtest ends, innermost first.
+func TestSnapshot(t *testing.T) {
+	fake := NewFake(t)
+	a := fake.Anvil(t)
+
+	t.Run("isolated", func(t *testing.T) {
+		Snapshot(t, a)
+		Snapshot(t, a)
+		if n := len(fake.Calls("evm_snapshot")); n != 2 {
+			t.Fatalf("expected 2 snapshots, got %d", n)
+		}
+	})
+
+	var reverts []string
+	for _, call := range fake.Calls("evm_revert") {
+		var id string
+		json.Unmarshal(call.Params[0], &id)
+		reverts = append(reverts, id)
+	}
+	if !slices.Equal(reverts, []string{"0x2", "0x1"}) {
+		t.Fatalf("unexpected reverts %v", reverts)
+	}
+}
@@ -1,5 +1,10 @@
 package anvil
 
+import (
+	"io"
+	"time"
+)
+
 // AnvilConfig configures how the Anvil process is started.
 // Zero values generally mean "omit this flag and let Anvil use its default".
 type Config struct {
@@ -120,9 +125,9 @@ type Config struct {
 	order string
 
 	// port number to listen on.
+	// 0 => a free ephemeral port is chosen at startup.
 	//
 	// CLI: -p, --port
-	// Default: 8545
 	port uint
 
 	// Preserve historical state snapshots when dumping state.
@@ -166,9 +171,31 @@ type Config struct {
 	// CLI: --transaction-block-keeper
 	transactionBlockKeeper uint64
 
+	// Path to the anvil binary. Empty => look it up on PATH, then in
+	// $FOUNDRY_DIR/bin and ~/.foundry/bin.
+	binaryPath string
+
+	// What to do when the installed Anvil is too old for the configured flags.
+	versionCheck VersionCheck
+
 	// Show logs during execution.
 	showLogs bool
 
+	// Receives every line of Anvil output, in addition to os.Stdout when showLogs is set.
+	logWriter io.Writer
+
+	// Number of recent log lines kept in memory and returned by Anvil.Logs.
+	logBufferSize int
+
+	// How long NewWithConfig waits for Anvil to start answering requests.
+	startupTimeout time.Duration
+
+	// How long Close waits for Anvil to exit after SIGTERM before killing it.
+	shutdownTimeout time.Duration
+
+	// Read the --config-out JSON after startup and expose it as Anvil.StartupReport.
+	startupReport bool
+
 	// Log color mode: "auto", "always", or "never".
 	//
 	// CLI: --color
@@ -407,6 +434,7 @@ func (c *Config) SetConfigOut(path string) *Config {
 }
 
 // SetDerivationPath sets DerivationPath, the BIP32 derivation path used for deriving accounts.
+// Anvil appends the account index to path as is, so it normally ends in "/".
 // If unset, Anvil uses its default "m/44'/60'/0'/0/".
 func (c *Config) SetDerivationPath(path string) *Config {
 	c.derivationPath = path
@@ -435,6 +463,7 @@ func (c *Config) SetInitPath(path string) *Config {
 
 // SetIPC enables or disables the IPC server via IPC, and optionally sets IPCPath.
 // If IPC is true and IPCPath is empty, Anvil uses its default (/tmp/anvil.ipc).
+// When enabled, all RPC calls made through the Anvil handle use the IPC socket.
 func (c *Config) SetIPC(enabled bool, path string) *Config {
 	c.ipc = enabled
 	c.ipcPath = path
@@ -527,7 +556,8 @@ func (c *Config) SetOrder(order string) *Config {
 }
 
 // SetPort sets Port, the port number Anvil listens on.
-// If unset, Anvil uses its default (8545).
+// If unset, a free ephemeral port is chosen so multiple instances can run in parallel;
+// use HttpUrl or WsUrl to find out which one.
 func (c *Config) SetPort(port uint) *Config {
 	c.port = port
 	return c
@@ -593,6 +623,63 @@ func (c *Config) SetShowLogs(show bool) *Config {
 	return c
 }
 
+// SetBinaryPath sets BinaryPath, the path of the anvil binary to run.
+// If unset, anvil is looked up on PATH, then in $FOUNDRY_DIR/bin and ~/.foundry/bin.
+func (c *Config) SetBinaryPath(path string) *Config {
+	c.binaryPath = path
+	return c
+}
+
+// SetVersionCheck sets VersionCheck, what NewWithConfig does when `anvil --version`
+// reports a release older than a configured flag requires, or fails.
+// `anvil --version` only runs if a configured flag has a known minimum version.
+// If unset, a warning is written and Anvil is started anyway.
+func (c *Config) SetVersionCheck(mode VersionCheck) *Config {
+	c.versionCheck = mode
+	return c
+}
+
+// SetLogWriter sets LogWriter, a writer that receives every line Anvil writes to
+// stdout and stderr. Each line is passed in a single Write call including its
+// trailing newline, which makes it easy to forward logs to t.Log or a logger.
+// This is independent of ShowLogs.
+func (c *Config) SetLogWriter(w io.Writer) *Config {
+	c.logWriter = w
+	return c
+}
+
+// SetLogBufferSize sets LogBufferSize, the number of recent log lines kept in
+// memory and returned by Anvil.Logs.
+// If unset, the last 1000 lines are kept.
+func (c *Config) SetLogBufferSize(n int) *Config {
+	c.logBufferSize = n
+	return c
+}
+
+// SetStartupTimeout sets StartupTimeout, how long NewWithConfig waits for Anvil to
+// start answering requests before giving up. Forking from a slow endpoint may need more.
+// If unset, a default of 100 seconds is used.
+func (c *Config) SetStartupTimeout(timeout time.Duration) *Config {
+	c.startupTimeout = timeout
+	return c
+}
+
+// SetShutdownTimeout sets ShutdownTimeout, how long Close waits for Anvil to exit
+// gracefully (e.g. while dumping state) before killing the process.
+// If unset, a default of 10 seconds is used.
+func (c *Config) SetShutdownTimeout(timeout time.Duration) *Config {
+	c.shutdownTimeout = timeout
+	return c
+}
+
+// SetStartupReport sets StartupReport, whether NewWithConfig reads the JSON
+// Anvil writes with --config-out and exposes it as Anvil.StartupReport. If no
+// path was set with SetConfigOut, a temporary file is used and removed again.
+func (c *Config) SetStartupReport(enabled bool) *Config {
+	c.startupReport = enabled
+	return c
+}
+
 // SetColor sets Color, the log color mode ("auto", "always", or "never").
 func (c *Config) SetColor(color string) *Config {
 	c.color = color
@@ -600,6 +687,7 @@ func (c *Config) SetColor(color string) *Config {
 }
 
 // SetJSONLogs sets JSONLogs, enabling or disabling JSON-formatted log output.
+// When enabled, lines returned by Anvil.Logs also carry the parsed LogRecord.
 func (c *Config) SetJSONLogs(enabled bool) *Config {
 	c.jsonLogs = enabled
 	return c
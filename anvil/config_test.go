@@ -0,0 +1,56 @@
+package anvil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestConfig_Validate checks that contradictory and malformed values are all reported.
+func TestConfig_Validate(t *testing.T) {
+	if err := NewConfig().Validate(); err != nil {
+		t.Fatalf("default config is invalid: %v", err)
+	}
+
+	valid := NewConfig().
+		SetForkURL("https://ethereum-rpc.publicnode.com").
+		SetForkBlockNumber(20000000).
+		SetHardfork("Spurious-Dragon").
+		SetOrder("fifo").
+		SetBalance("100").
+		SetGasPrice("0x3b9aca00")
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	invalid := NewConfig().
+		SetQuiet(true).
+		SetVerbosity(2).
+		SetJSONLogs(true).
+		SetMarkdownLogs(true).
+		SetMnemonic("test test test test test test test test test test test junk").
+		SetMnemonicRandom(true).
+		SetBalance("lots").
+		SetForkBlockNumber(100).
+		SetHardfork("homer").
+		SetOrder("random")
+
+	err := invalid.Validate()
+
+	var configErr *ConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("expected *ConfigError, got %T: %v", err, err)
+	}
+	if len(configErr.Errors) != 7 {
+		t.Fatalf("expected 7 problems, got %d: %v", len(configErr.Errors), err)
+	}
+	for _, want := range []string{"SetQuiet", "SetJSONLogs", "SetMnemonic", "balance", "SetForkURL", "homer", "random"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q: %v", want, err)
+		}
+	}
+
+	if _, err := NewWithConfig(invalid); !errors.As(err, &configErr) {
+		t.Fatalf("NewWithConfig did not validate the config: %v", err)
+	}
+}
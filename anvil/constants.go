@@ -1,5 +1,18 @@
 package anvil
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 var account0 = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+// defaultIPCPath is the socket Anvil listens on when --ipc is passed without a path.
+const defaultIPCPath = "/tmp/anvil.ipc"
+
+// defaultStartupTimeout bounds how long NewWithConfig waits for Anvil to become ready.
+const defaultStartupTimeout = 100 * time.Second
+
+// defaultShutdownTimeout is how long Close waits for Anvil to exit before killing it.
+const defaultShutdownTimeout = 10 * time.Second
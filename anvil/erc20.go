@@ -0,0 +1,172 @@
+package anvil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// defaultMaxProbeSlot is the highest storage slot DealERC20 probes by default.
+const defaultMaxProbeSlot = 64
+
+var (
+	balanceOfSelector   = common.FromHex("0x70a08231")
+	totalSupplySelector = common.FromHex("0x18160ddd")
+
+	// probeValue is written to candidate slots while probing. It is unlikely
+	// to be the real balance or supply of any token.
+	probeValue = common.HexToHash("0x00000000000000000000000000000000000000000000000000000000deadbeef")
+)
+
+// ErrSlotNotFound is returned by DealERC20 when the storage slot of a token
+// balance or total supply cannot be discovered.
+var ErrSlotNotFound = errors.New("storage slot not found")
+
+// DealERC20Options configures DealERC20.
+type DealERC20Options struct {
+	// AdjustTotalSupply also changes totalSupply by the difference between
+	// amount and the previous balance of holder.
+	AdjustTotalSupply bool
+	// MaxProbeSlot is the highest storage slot probed when looking for the
+	// balance mapping and total supply. Defaults to 64.
+	MaxProbeSlot uint64
+}
+
+// DealERC20 sets the token balance of holder to amount. It uses
+// anvil_dealERC20 when the node supports it. If the node reports that method
+// as not found, DealERC20 discovers the slot of the balance mapping by
+// overriding candidate slots in an eth_call to balanceOf, covering both the
+// Solidity and the Vyper mapping layouts, and writes the balance with
+// SetStorageAt. Since the override applies to the storage of the token
+// address, tokens behind a proxy are supported too. Any other error from
+// anvil_dealERC20 is returned as is.
+//
+// With AdjustTotalSupply, DealERC20 fails without changing any balance if
+// the adjusted total supply would be negative or its slot cannot be found.
+//
+// opts may be nil.
+func (a Anvil) DealERC20(token, holder common.Address, amount *big.Int, opts *DealERC20Options) error {
+	return a.DealERC20Context(context.Background(), token, holder, amount, opts)
+}
+
+// DealERC20Context is like DealERC20 but honors ctx for cancellation and deadlines.
+func (a Anvil) DealERC20Context(ctx context.Context, token, holder common.Address, amount *big.Int, opts *DealERC20Options) error {
+	if opts == nil {
+		opts = &DealERC20Options{}
+	}
+	maxSlot := opts.MaxProbeSlot
+	if maxSlot == 0 {
+		maxSlot = defaultMaxProbeSlot
+	}
+
+	balanceOf := append(common.CopyBytes(balanceOfSelector), common.LeftPadBytes(holder.Bytes(), 32)...)
+	previous, err := a.callUint(ctx, token, balanceOf, nil)
+	if err != nil {
+		return fmt.Errorf("error reading balance: %w", err)
+	}
+
+	var supply *big.Int
+	var supplySlot common.Hash
+	if opts.AdjustTotalSupply && amount.Cmp(previous) != 0 {
+		supply, err = a.callUint(ctx, token, totalSupplySelector, nil)
+		if err != nil {
+			return fmt.Errorf("error reading total supply: %w", err)
+		}
+		supply.Add(supply, new(big.Int).Sub(amount, previous))
+		if supply.Sign() < 0 {
+			return fmt.Errorf("total supply of token %s would become negative (%s)", token, supply)
+		}
+		// Find the slot before writing anything, so that a failed search
+		// leaves the balance alone.
+		if supplySlot, err = a.totalSupplySlot(ctx, token, maxSlot); err != nil {
+			return err
+		}
+	}
+
+	_, err = makeRequest[any](ctx, a.rpcClient, "anvil_dealERC20", []any{holder, token, toHexQuantityBig(amount)})
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == codeMethodNotFound {
+		err = a.dealERC20Storage(ctx, token, holder, amount, balanceOf, maxSlot)
+	}
+	if err != nil {
+		return err
+	}
+
+	if supply == nil {
+		return nil
+	}
+	_, err = a.SetStorageAtContext(ctx, token, supplySlot, common.BigToHash(supply))
+	return err
+}
+
+// dealERC20Storage sets the balance by writing the balance mapping directly.
+func (a Anvil) dealERC20Storage(ctx context.Context, token, holder common.Address, amount *big.Int, balanceOf []byte, maxSlot uint64) error {
+	key := common.BytesToHash(holder.Bytes())
+	for slot := uint64(0); slot <= maxSlot; slot++ {
+		for _, candidate := range []common.Hash{MappingSlot(Slot(slot), key), VyperMappingSlot(Slot(slot), key)} {
+			found, err := a.probeSlot(ctx, token, balanceOf, candidate)
+			if err != nil {
+				return err
+			}
+			if found {
+				_, err := a.SetStorageAtContext(ctx, token, candidate, common.BigToHash(amount))
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("balance of %s in token %s: %w", holder, token, ErrSlotNotFound)
+}
+
+// totalSupplySlot returns the storage slot holding the total supply of token.
+func (a Anvil) totalSupplySlot(ctx context.Context, token common.Address, maxSlot uint64) (common.Hash, error) {
+	for slot := uint64(0); slot <= maxSlot; slot++ {
+		candidate := Slot(slot)
+		found, err := a.probeSlot(ctx, token, totalSupplySelector, candidate)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if found {
+			return candidate, nil
+		}
+	}
+	return common.Hash{}, fmt.Errorf("total supply of token %s: %w", token, ErrSlotNotFound)
+}
+
+// probeSlot reports whether overriding slot of token changes the result of
+// calling token with data to probeValue.
+func (a Anvil) probeSlot(ctx context.Context, token common.Address, data []byte, slot common.Hash) (bool, error) {
+	override := map[common.Address]any{
+		token: map[string]any{"stateDiff": map[common.Hash]common.Hash{slot: probeValue}},
+	}
+	got, err := a.callUint(ctx, token, data, override)
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.reverted() {
+		// Overriding some slots makes the call revert; that is not the one.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return got.Cmp(probeValue.Big()) == 0, nil
+}
+
+// callUint calls token with data at the latest block and decodes the result
+// as a uint256. override is an optional eth_call state override set.
+func (a Anvil) callUint(ctx context.Context, token common.Address, data []byte, override map[common.Address]any) (*big.Int, error) {
+	params := []any{map[string]any{"to": token, "input": hexutil.Bytes(data)}, "latest"}
+	if override != nil {
+		params = append(params, override)
+	}
+	res, err := makeRequest[hexutil.Bytes](ctx, a.rpcClient, "eth_call", params)
+	if err != nil {
+		return nil, err
+	}
+	if len(*res) != 32 {
+		return nil, fmt.Errorf("unexpected %d byte result from %s", len(*res), token)
+	}
+	return new(big.Int).SetBytes(*res), nil
+}
@@ -0,0 +1,162 @@
+package anvil
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// testToken serves a fake Vyper token keeping balances at slot 3 and the
+// total supply at slot 5. dealErr is the answer to anvil_dealERC20 and
+// probeErr, if set, the answer to every eth_call with a state override.
+type testToken struct {
+	t        *testing.T
+	address  common.Address
+	storage  map[common.Hash]common.Hash
+	dealErr  *RPCError
+	probeErr *RPCError
+}
+
+var (
+	testTokenAddress = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	testTokenHolder  = common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	testBalanceSlot  = VyperMappingSlot(Slot(3), common.BytesToHash(testTokenHolder.Bytes()))
+	testSupplySlot   = Slot(5)
+)
+
+func newTestToken(t *testing.T, balance, supply int64, dealErr *RPCError) *testToken {
+	return &testToken{
+		t:       t,
+		address: testTokenAddress,
+		storage: map[common.Hash]common.Hash{
+			testBalanceSlot: common.BigToHash(big.NewInt(balance)),
+			testSupplySlot:  common.BigToHash(big.NewInt(supply)),
+		},
+		dealErr: dealErr,
+	}
+}
+
+func (tok *testToken) handle(method string, params []json.RawMessage) any {
+	switch method {
+	case "anvil_dealERC20":
+		return tok.dealErr
+	case "anvil_setStorageAt":
+		var slot, value common.Hash
+		json.Unmarshal(params[1], &slot)
+		json.Unmarshal(params[2], &value)
+		tok.storage[slot] = value
+		return true
+	case "eth_call":
+		var call struct {
+			Input hexutil.Bytes `json:"input"`
+		}
+		json.Unmarshal(params[0], &call)
+		var overrides map[common.Address]struct {
+			StateDiff map[common.Hash]common.Hash `json:"stateDiff"`
+		}
+		if len(params) > 2 {
+			if tok.probeErr != nil {
+				return tok.probeErr
+			}
+			json.Unmarshal(params[2], &overrides)
+		}
+		read := func(slot common.Hash) common.Hash {
+			if v, ok := overrides[tok.address].StateDiff[slot]; ok {
+				return v
+			}
+			return tok.storage[slot]
+		}
+		if len(call.Input) == 4 {
+			return hexutil.Bytes(read(testSupplySlot).Bytes())
+		}
+		return hexutil.Bytes(read(testBalanceSlot).Bytes())
+	}
+	tok.t.Errorf("unexpected method %s", method)
+	return nil
+}
+
+// TestAnvil_DealERC20Fallback checks that DealERC20 finds the balance and total
+// supply slots by probing when anvil_dealERC20 is not available.
+func TestAnvil_DealERC20Fallback(t *testing.T) {
+	tok := newTestToken(t, 10, 1000, &RPCError{Code: codeMethodNotFound, Message: "method not found"})
+	anvl := stubAnvil(t, tok.handle)
+
+	err := anvl.DealERC20(tok.address, testTokenHolder, big.NewInt(500), &DealERC20Options{AdjustTotalSupply: true})
+	if err != nil {
+		t.Fatalf("DealERC20 failed: %v", err)
+	}
+
+	if got := tok.storage[testBalanceSlot].Big(); got.Int64() != 500 {
+		t.Fatalf("balance is %s, expected 500", got)
+	}
+	if got := tok.storage[testSupplySlot].Big(); got.Int64() != 1490 {
+		t.Fatalf("total supply is %s, expected 1490", got)
+	}
+}
+
+// TestAnvil_DealERC20Errors checks that node errors other than method not
+// found are returned, and that a negative total supply is refused.
+func TestAnvil_DealERC20Errors(t *testing.T) {
+	tok := newTestToken(t, 10, 1000, &RPCError{Code: -32603, Message: "internal error"})
+	anvl := stubAnvil(t, tok.handle)
+
+	err := anvl.DealERC20(tok.address, testTokenHolder, big.NewInt(500), nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32603 {
+		t.Fatalf("expected the anvil_dealERC20 error, got %v", err)
+	}
+	if got := tok.storage[testBalanceSlot].Big(); got.Int64() != 10 {
+		t.Fatalf("balance changed to %s after a failed deal", got)
+	}
+
+	tok = newTestToken(t, 10, 5, &RPCError{Code: codeMethodNotFound, Message: "method not found"})
+	anvl = stubAnvil(t, tok.handle)
+
+	err = anvl.DealERC20(tok.address, testTokenHolder, big.NewInt(0), &DealERC20Options{AdjustTotalSupply: true})
+	if err == nil {
+		t.Fatalf("DealERC20 accepted a negative total supply")
+	}
+	if got := tok.storage[testBalanceSlot].Big(); got.Int64() != 10 {
+		t.Fatalf("balance changed to %s after a refused deal", got)
+	}
+	if got := tok.storage[testSupplySlot].Big(); got.Int64() != 5 {
+		t.Fatalf("total supply changed to %s after a refused deal", got)
+	}
+}
+
+// TestAnvil_DealERC20Probe checks that only reverts are treated as probe
+// misses, and that a missing supply slot leaves the balance alone.
+func TestAnvil_DealERC20Probe(t *testing.T) {
+	notFound := &RPCError{Code: codeMethodNotFound, Message: "method not found"}
+
+	tok := newTestToken(t, 10, 1000, notFound)
+	tok.probeErr = &RPCError{Code: codeExecutionReverted, Message: "execution reverted", Data: json.RawMessage(`"0x"`)}
+	anvl := stubAnvil(t, tok.handle)
+	err := anvl.DealERC20(tok.address, testTokenHolder, big.NewInt(500), nil)
+	if !errors.Is(err, ErrSlotNotFound) {
+		t.Fatalf("expected ErrSlotNotFound when every probe reverts, got %v", err)
+	}
+
+	tok = newTestToken(t, 10, 1000, notFound)
+	tok.probeErr = &RPCError{Code: -32602, Message: "invalid params"}
+	anvl = stubAnvil(t, tok.handle)
+	err = anvl.DealERC20(tok.address, testTokenHolder, big.NewInt(500), nil)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+		t.Fatalf("expected the probe error, got %v", err)
+	}
+
+	tok = newTestToken(t, 10, 1000, notFound)
+	anvl = stubAnvil(t, tok.handle)
+	err = anvl.DealERC20(tok.address, testTokenHolder, big.NewInt(500), &DealERC20Options{AdjustTotalSupply: true, MaxProbeSlot: 4})
+	if !errors.Is(err, ErrSlotNotFound) {
+		t.Fatalf("expected ErrSlotNotFound for the total supply, got %v", err)
+	}
+	if got := tok.storage[testBalanceSlot].Big(); got.Int64() != 10 {
+		t.Fatalf("balance changed to %s although the supply slot was not found", got)
+	}
+}
@@ -0,0 +1,141 @@
+package anvil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// codeMethodNotFound is the JSON-RPC error code for a method the node does not support.
+	codeMethodNotFound = -32601
+	// codeExecutionReverted is the JSON-RPC error code for a reverted call.
+	codeExecutionReverted = 3
+)
+
+// RPCError is a JSON-RPC error object returned by Anvil when it rejects a request.
+// Transport failures (connection refused, timeouts, malformed responses) are
+// returned as ordinary errors, so errors.As(err, &rpcErr) can be used to tell
+// the two apart.
+//
+// RPCError implements the rpc.Error and rpc.DataError interfaces from go-ethereum.
+type RPCError struct {
+	// Method is the JSON-RPC method that was rejected.
+	Method string `json:"-"`
+
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error returns the error message along with the rejected method and error code.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s: %s (code %d)", e.Method, e.Message, e.Code)
+}
+
+// ErrorCode returns the JSON-RPC error code.
+func (e *RPCError) ErrorCode() int {
+	return e.Code
+}
+
+// ErrorData returns the decoded "data" field of the error object, or nil if absent.
+func (e *RPCError) ErrorData() any {
+	if len(e.Data) == 0 {
+		return nil
+	}
+	var data any
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return nil
+	}
+	return data
+}
+
+// RevertData returns the raw revert data carried by the error, if the "data"
+// field holds a 0x-prefixed hex string as Anvil returns for reverted calls.
+func (e *RPCError) RevertData() ([]byte, bool) {
+	var s string
+	if err := json.Unmarshal(e.Data, &s); err != nil {
+		return nil, false
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+// reverted reports whether the error is an execution revert rather than a
+// rejected request.
+func (e *RPCError) reverted() bool {
+	if _, ok := e.RevertData(); ok {
+		return true
+	}
+	return e.Code == codeExecutionReverted
+}
+
+// toRPCError converts an error object returned by the node into an *RPCError.
+// Any other error is treated as a transport failure and wrapped as is.
+func toRPCError(method string, err error) error {
+	var codeErr rpc.Error
+	if !errors.As(err, &codeErr) {
+		return fmt.Errorf("error sending %s request: %w", method, err)
+	}
+
+	rpcErr := &RPCError{
+		Method:  method,
+		Code:    codeErr.ErrorCode(),
+		Message: codeErr.Error(),
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+		if data, err := json.Marshal(dataErr.ErrorData()); err == nil {
+			rpcErr.Data = data
+		}
+	}
+
+	return rpcErr
+}
+
+// StartError is returned by NewWithConfig when the Anvil process exits
+// before it starts answering requests, e.g. because of an invalid flag,
+// an unreachable fork URL or a port that is already in use.
+type StartError struct {
+	// ExitCode is the exit code of the Anvil process, or -1 if it was
+	// terminated by a signal.
+	ExitCode int
+	// Stderr holds the last lines Anvil wrote to stderr before exiting.
+	Stderr string
+}
+
+func (e *StartError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("Anvil exited during startup with code %d", e.ExitCode)
+	}
+	return fmt.Sprintf("Anvil exited during startup with code %d: %s", e.ExitCode, e.Stderr)
+}
+
+// newStartError builds a *StartError for a process that has already exited.
+func newStartError(proc *process, logs *logSink) *StartError {
+	var stderr []string
+	for _, line := range logs.ring.last(0) {
+		if line.Stderr {
+			stderr = append(stderr, line.Text)
+		}
+	}
+	if len(stderr) > startErrorLines {
+		stderr = stderr[len(stderr)-startErrorLines:]
+	}
+
+	return &StartError{
+		ExitCode: proc.cmd.ProcessState.ExitCode(),
+		Stderr:   strings.Join(stderr, "\n"),
+	}
+}
+
+// startErrorLines is the number of stderr lines included in a StartError.
+const startErrorLines = 20
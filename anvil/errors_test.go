@@ -0,0 +1,80 @@
+package anvil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestAnvil_RPCError checks that JSON-RPC error objects surface as *RPCError.
+func TestAnvil_RPCError(t *testing.T) {
+	srv := newStubServer(t, func(method string, params []json.RawMessage) any {
+		return &RPCError{Code: 3, Message: "execution reverted", Data: json.RawMessage(`"0x08c379a0"`)}
+	})
+	anvl := dialTestAnvil(t, srv.URL)
+
+	_, err := anvl.SetStorageAt(account0, common.Hash{}, common.Hash{})
+	if err == nil {
+		t.Fatalf("SetStorageAt succeeded on a rejected call")
+	}
+
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected *RPCError, got %T: %v", err, err)
+	}
+	if rpcErr.Method != "anvil_setStorageAt" || rpcErr.Code != 3 {
+		t.Fatalf("unexpected error fields: %+v", rpcErr)
+	}
+
+	data, ok := rpcErr.RevertData()
+	if !ok || !bytes.Equal(data, []byte{0x08, 0xc3, 0x79, 0xa0}) {
+		t.Fatalf("unexpected revert data: %x (ok=%v)", data, ok)
+	}
+}
+
+// TestAnvil_TransportError checks that transport failures are not reported as *RPCError.
+func TestAnvil_TransportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	anvl := dialTestAnvil(t, srv.URL)
+
+	err := anvl.SetCoinbase(account0)
+	if err == nil {
+		t.Fatalf("SetCoinbase succeeded against a failing transport")
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		t.Fatalf("transport failure reported as *RPCError: %v", err)
+	}
+}
+
+// TestAnvil_ContextDeadline checks that a context deadline aborts a hung request.
+func TestAnvil_ContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	anvl := dialTestAnvil(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := anvl.MineContext(ctx, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
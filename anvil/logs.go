@@ -0,0 +1,156 @@
+package anvil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+)
+
+// defaultLogBufferSize is the number of recent log lines kept per instance.
+const defaultLogBufferSize = 1000
+
+// LogLine is a single line of output written by the Anvil process.
+type LogLine struct {
+	// Stderr is true if the line was written to stderr rather than stdout.
+	Stderr bool
+	// Text is the raw line, without the trailing newline.
+	Text string
+	// Record is the parsed log record when SetJSONLogs is enabled and the line
+	// is a JSON object, and nil otherwise.
+	Record *LogRecord
+}
+
+// LogRecord is a structured log record as printed by Anvil with --json.
+type LogRecord struct {
+	Timestamp string         `json:"timestamp"`
+	Level     string         `json:"level"`
+	Target    string         `json:"target"`
+	Fields    map[string]any `json:"fields"`
+}
+
+// Message returns the "message" field of the record, if any.
+func (r *LogRecord) Message() string {
+	msg, _ := r.Fields["message"].(string)
+	return msg
+}
+
+// logSink fans out lines of Anvil output to the in-memory ring buffer and to
+// the writer configured with SetLogWriter.
+type logSink struct {
+	ring *logRing
+	json bool
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newLogSink(size int, w io.Writer, json bool) *logSink {
+	return &logSink{
+		ring: newLogRing(size),
+		json: json,
+		w:    w,
+	}
+}
+
+// write records a single line. Each line is passed to the configured writer
+// with its own Write call, so line-oriented writers such as t.Log adapters
+// never see partial or interleaved lines.
+func (s *logSink) write(stderr bool, text string) {
+	line := LogLine{Stderr: stderr, Text: text}
+	if s.json && strings.HasPrefix(text, "{") {
+		var record LogRecord
+		if err := json.Unmarshal([]byte(text), &record); err == nil {
+			line.Record = &record
+		}
+	}
+	s.ring.add(line)
+
+	if s.w == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.w.Write([]byte(text + "\n"))
+}
+
+// logRing keeps the most recent lines written by Anvil.
+type logRing struct {
+	mu    sync.Mutex
+	lines []LogLine
+	next  int
+	full  bool
+}
+
+func newLogRing(size int) *logRing {
+	return &logRing{lines: make([]LogLine, size)}
+}
+
+func (r *logRing) add(line LogLine) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.lines) == 0 {
+		return
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// last returns up to n of the most recent lines, oldest first.
+// If n <= 0, every buffered line is returned.
+func (r *logRing) last(n int) []LogLine {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.lines)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+
+	out := make([]LogLine, 0, n)
+	for i := count - n; i < count; i++ {
+		idx := i
+		if r.full {
+			idx = (r.next + i) % len(r.lines)
+		}
+		out = append(out, r.lines[idx])
+	}
+	return out
+}
+
+// lineWriter is an io.Writer that calls fn for every complete line written to it.
+type lineWriter struct {
+	mu  sync.Mutex
+	buf []byte
+	fn  func(line string)
+}
+
+func newLineWriter(fn func(line string)) *lineWriter {
+	return &lineWriter{fn: fn}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(w.buf[:i], "\r"))
+		w.buf = w.buf[i+1:]
+		w.fn(line)
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,50 @@
+package anvil
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// TestAnvil_LogRing checks that the ring buffer keeps only the most recent lines in order.
+func TestAnvil_LogRing(t *testing.T) {
+	ring := newLogRing(3)
+	for i := range 5 {
+		ring.add(LogLine{Text: fmt.Sprint(i)})
+	}
+
+	lines := ring.last(0)
+	if len(lines) != 3 || lines[0].Text != "2" || lines[2].Text != "4" {
+		t.Fatalf("unexpected lines: %+v", lines)
+	}
+
+	lines = ring.last(2)
+	if len(lines) != 2 || lines[0].Text != "3" || lines[1].Text != "4" {
+		t.Fatalf("unexpected lines: %+v", lines)
+	}
+}
+
+// TestAnvil_LogSink checks line splitting, writer forwarding and JSON record parsing.
+func TestAnvil_LogSink(t *testing.T) {
+	var out bytes.Buffer
+	sink := newLogSink(10, &out, true)
+	w := newLineWriter(func(line string) { sink.write(false, line) })
+
+	fmt.Fprint(w, `{"timestamp":"2025-01-01T00:00:00Z","level":"INFO","fields":{"message":"eth_blockNumber"},"target":"rpc"}`)
+	fmt.Fprint(w, "\nplain line\n")
+
+	if out.String() != sink.ring.last(0)[0].Text+"\nplain line\n" {
+		t.Fatalf("unexpected writer output: %q", out.String())
+	}
+
+	lines := sink.ring.last(0)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if lines[0].Record == nil || lines[0].Record.Level != "INFO" || lines[0].Record.Message() != "eth_blockNumber" {
+		t.Fatalf("unexpected record: %+v", lines[0].Record)
+	}
+	if lines[1].Record != nil {
+		t.Fatalf("plain line parsed as a record: %+v", lines[1].Record)
+	}
+}
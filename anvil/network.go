@@ -0,0 +1,223 @@
+package anvil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// defaultChainID is the chain ID Anvil uses when none is configured.
+const defaultChainID = 31337
+
+// Network is a set of Anvil instances run side by side, e.g. an L1 and one
+// or more L2s for cross-chain tests. Chains are addressed by the names given
+// to NewNetwork.
+type Network struct {
+	names  []string
+	chains map[string]*Anvil
+}
+
+// NewNetwork starts one instance per entry of configs, keyed by chain name,
+// and waits until all of them are ready. The chains must have distinct chain
+// IDs. Chain IDs known from the configs are checked before anything is
+// started: SetChainID, else SetForkChainID, else Anvil's default of 31337
+// for configs that do not fork. The chain IDs of forks are only known once
+// they run, so all chain IDs are compared again after startup. Use
+// SetOptimism on the configs of OP stack L2s.
+//
+// If any instance fails to start, or two chains share a chain ID, the
+// instances are closed again.
+func NewNetwork(configs map[string]*Config) (*Network, error) {
+	return NewNetworkContext(context.Background(), configs)
+}
+
+// NewNetworkContext is like NewNetwork but honors ctx for cancellation and
+// deadlines. Each instance is started within the startup timeout of its
+// config, and the chain ID check after startup is bounded by ctx and the
+// longest startup timeout, counted from the call.
+func NewNetworkContext(ctx context.Context, configs map[string]*Config) (*Network, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("network needs at least one chain")
+	}
+
+	names := slices.Sorted(maps.Keys(configs))
+	chainIDs := make(map[uint64]string, len(configs))
+	for _, name := range names {
+		config := configs[name]
+		if config == nil {
+			return nil, fmt.Errorf("chain %s has a nil config", name)
+		}
+		id, ok := configChainID(config)
+		if !ok {
+			continue
+		}
+		if other, ok := chainIDs[id]; ok {
+			return nil, fmt.Errorf("chains %s and %s both use chain ID %d", other, name, id)
+		}
+		chainIDs[id] = name
+	}
+
+	timeout := time.Duration(0)
+	for _, config := range configs {
+		timeout = max(timeout, startupTimeout(config))
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	n := &Network{names: names, chains: make(map[string]*Anvil, len(configs))}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			a, err := NewWithConfig(configs[name])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error starting chain %s: %w", name, err))
+				return
+			}
+			n.chains[name] = &a
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		if err := n.checkChainIDs(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		n.Close()
+		return nil, errors.Join(errs...)
+	}
+	return n, nil
+}
+
+// configChainID returns the chain ID an instance started with config will
+// use, if it is known without starting it.
+func configChainID(config *Config) (uint64, bool) {
+	switch {
+	case config.chainID != 0:
+		return config.chainID, true
+	case config.forkChainID != 0:
+		return config.forkChainID, true
+	case config.forkURL == "":
+		return defaultChainID, true
+	}
+	return 0, false
+}
+
+// checkChainIDs fails if two running chains report the same chain ID.
+func (n *Network) checkChainIDs(ctx context.Context) error {
+	chainIDs := make(map[uint64]string, len(n.names))
+	for _, name := range n.names {
+		id, err := makeRequest[hexutil.Uint64](ctx, n.chains[name].rpcClient, "eth_chainId", []any{})
+		if err != nil {
+			return fmt.Errorf("error reading chain ID of chain %s: %w", name, err)
+		}
+		if other, ok := chainIDs[uint64(*id)]; ok {
+			return fmt.Errorf("chains %s and %s both use chain ID %d", other, name, uint64(*id))
+		}
+		chainIDs[uint64(*id)] = name
+	}
+	return nil
+}
+
+// Chain returns the instance started for name, or nil if there is none.
+func (n *Network) Chain(name string) *Anvil {
+	return n.chains[name]
+}
+
+// Names returns the chain names in sorted order.
+func (n *Network) Names() []string {
+	return slices.Clone(n.names)
+}
+
+// Mine mines blocks blocks on every chain in lockstep. Round i mines one block
+// on each chain, in name order, with the same timestamp on all of them; the
+// first round uses one second after the latest block on any chain, and each
+// further round one second more.
+func (n *Network) Mine(blocks uint64) error {
+	return n.MineContext(context.Background(), blocks)
+}
+
+// MineContext is like Mine but honors ctx for cancellation and deadlines.
+func (n *Network) MineContext(ctx context.Context, blocks uint64) error {
+	latest, err := n.latestTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+	for i := uint64(1); i <= blocks; i++ {
+		if err := n.mineAt(ctx, latest+i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AdvanceTime moves every chain seconds past the latest block on any chain
+// by mining one block with that timestamp on each of them, so all chains
+// end up with the same latest timestamp.
+func (n *Network) AdvanceTime(seconds uint64) error {
+	return n.AdvanceTimeContext(context.Background(), seconds)
+}
+
+// AdvanceTimeContext is like AdvanceTime but honors ctx for cancellation and deadlines.
+func (n *Network) AdvanceTimeContext(ctx context.Context, seconds uint64) error {
+	latest, err := n.latestTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+	return n.mineAt(ctx, latest+seconds)
+}
+
+// mineAt mines one block with timestamp on every chain.
+func (n *Network) mineAt(ctx context.Context, timestamp uint64) error {
+	for _, name := range n.names {
+		if err := n.chains[name].EvmMineContext(ctx, timestamp); err != nil {
+			return fmt.Errorf("error mining on chain %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// latestTimestamp returns the highest latest block timestamp across all chains.
+func (n *Network) latestTimestamp(ctx context.Context) (uint64, error) {
+	var latest uint64
+	for _, name := range n.names {
+		block, err := makeRequest[struct {
+			Timestamp hexutil.Uint64 `json:"timestamp"`
+		}](ctx, n.chains[name].rpcClient, "eth_getBlockByNumber", []any{"latest", false})
+		if err != nil {
+			return 0, fmt.Errorf("error reading latest block of chain %s: %w", name, err)
+		}
+		latest = max(latest, uint64(block.Timestamp))
+	}
+	return latest, nil
+}
+
+// Close stops every chain of the network.
+func (n *Network) Close() error {
+	var errs []error
+	for _, name := range n.names {
+		if a, ok := n.chains[name]; ok {
+			if err := a.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("error closing chain %s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+package anvil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// chainStub serves eth_chainId, eth_getBlockByNumber and evm_mine for a
+// single fake chain.
+type chainStub struct {
+	chainID   uint64
+	timestamp uint64
+	mined     []uint64
+}
+
+func (c *chainStub) handle(method string, params []json.RawMessage) any {
+	switch method {
+	case "eth_chainId":
+		return toHexQuantityUint64(c.chainID)
+	case "eth_getBlockByNumber":
+		return map[string]any{"timestamp": toHexQuantityUint64(c.timestamp)}
+	case "evm_mine":
+		var ts string
+		json.Unmarshal(params[0], &ts)
+		var q quantity
+		q.parse(ts)
+		c.timestamp = q.Uint64()
+		c.mined = append(c.mined, c.timestamp)
+		return "0x0"
+	}
+	return nil
+}
+
+// TestAnvil_NetworkLockstep checks that all chains are mined with common timestamps.
+func TestAnvil_NetworkLockstep(t *testing.T) {
+	l1 := &chainStub{timestamp: 1000}
+	l2 := &chainStub{timestamp: 1500}
+	a1, a2 := stubAnvil(t, l1.handle), stubAnvil(t, l2.handle)
+	n := &Network{names: []string{"l1", "l2"}, chains: map[string]*Anvil{"l1": &a1, "l2": &a2}}
+
+	if err := n.Mine(2); err != nil {
+		t.Fatalf("Mine failed: %v", err)
+	}
+	if err := n.AdvanceTime(60); err != nil {
+		t.Fatalf("AdvanceTime failed: %v", err)
+	}
+
+	want := []uint64{1501, 1502, 1562}
+	if !slices.Equal(l1.mined, want) || !slices.Equal(l2.mined, want) {
+		t.Fatalf("chains mined at %v and %v, expected %v", l1.mined, l2.mined, want)
+	}
+}
+
+// TestAnvil_NetworkCheckChainIDs checks the chain ID comparison of running
+// chains and that it honors the startup context.
+func TestAnvil_NetworkCheckChainIDs(t *testing.T) {
+	a1, a2 := stubAnvil(t, (&chainStub{chainID: 1}).handle), stubAnvil(t, (&chainStub{chainID: 1}).handle)
+	n := &Network{names: []string{"l1", "l2"}, chains: map[string]*Anvil{"l1": &a1, "l2": &a2}}
+	if err := n.checkChainIDs(context.Background()); err == nil {
+		t.Fatalf("checkChainIDs accepted two chains with chain ID 1")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := n.checkChainIDs(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("checkChainIDs with a canceled context returned %v", err)
+	}
+}
+
+// TestAnvil_NetworkChainIDs checks that chains sharing a chain ID are rejected.
+func TestAnvil_NetworkChainIDs(t *testing.T) {
+	_, err := NewNetwork(map[string]*Config{
+		"l1": NewConfig(),
+		"l2": NewConfig().SetOptimism(true),
+	})
+	if err == nil {
+		t.Fatalf("NewNetwork accepted two chains with the default chain ID")
+	}
+
+	_, err = NewNetwork(map[string]*Config{
+		"l1": NewConfig().SetForkURL("http://localhost:1").SetForkChainID(1),
+		"l2": NewConfig().SetChainID(1),
+	})
+	if err == nil {
+		t.Fatalf("NewNetwork accepted a fork chain ID clashing with another chain")
+	}
+
+	if _, err := NewNetwork(map[string]*Config{"l1": nil}); err == nil {
+		t.Fatalf("NewNetwork accepted a nil config")
+	}
+
+	// Forks without SetForkChainID are only checked once running.
+	for _, config := range []*Config{NewConfig().SetForkURL("http://localhost:1"), NewConfig().SetForkURL("http://localhost:1").SetOptimism(true)} {
+		if id, ok := configChainID(config); ok {
+			t.Fatalf("configChainID guessed %d for a fork", id)
+		}
+	}
+
+	mainnet, op, mainnet2 := &chainStub{chainID: 1}, &chainStub{chainID: 10}, &chainStub{chainID: 1}
+	a1, a2, a3 := stubAnvil(t, mainnet.handle), stubAnvil(t, op.handle), stubAnvil(t, mainnet2.handle)
+
+	n := &Network{names: []string{"mainnet", "op"}, chains: map[string]*Anvil{"mainnet": &a1, "op": &a2}}
+	if err := n.checkChainIDs(context.Background()); err != nil {
+		t.Fatalf("checkChainIDs rejected distinct chains: %v", err)
+	}
+
+	n = &Network{names: []string{"a", "b"}, chains: map[string]*Anvil{"a": &a1, "b": &a3}}
+	if err := n.checkChainIDs(context.Background()); err == nil {
+		t.Fatalf("checkChainIDs accepted two forks of the same network")
+	}
+}
+
+// TestAnvil_Network starts an L1 and an L2 and mines them in lockstep.
+func TestAnvil_Network(t *testing.T) {
+	n, err := NewNetwork(map[string]*Config{
+		"l1": NewConfig().SetChainID(1),
+		"l2": NewConfig().SetChainID(10).SetOptimism(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+
+	if err := n.AdvanceTime(3600); err != nil {
+		t.Fatalf("AdvanceTime failed: %v", err)
+	}
+
+	l1, err := n.Chain("l1").NodeInfo()
+	if err != nil {
+		t.Fatalf("NodeInfo failed: %v", err)
+	}
+	l2, err := n.Chain("l2").NodeInfo()
+	if err != nil {
+		t.Fatalf("NodeInfo failed: %v", err)
+	}
+	if l1.CurrentBlockTimestamp != l2.CurrentBlockTimestamp {
+		t.Fatalf("chains are at timestamps %d and %d", l1.CurrentBlockTimestamp, l2.CurrentBlockTimestamp)
+	}
+}
@@ -0,0 +1,236 @@
+package anvil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NodeInfo is the result of anvil_nodeInfo.
+type NodeInfo struct {
+	CurrentBlockNumber    uint64
+	CurrentBlockTimestamp uint64
+	CurrentBlockHash      common.Hash
+	HardFork              string
+	TransactionOrder      string
+	Environment           NodeEnvironment
+	ForkConfig            NodeForkConfig
+	// Network is the network Anvil is configured for (e.g. "optimism"), or
+	// empty for plain Ethereum.
+	Network string
+}
+
+// NodeEnvironment holds the chain environment reported by anvil_nodeInfo.
+type NodeEnvironment struct {
+	BaseFee  *big.Int
+	ChainID  uint64
+	GasLimit uint64
+	GasPrice *big.Int
+}
+
+// NodeForkConfig holds the fork settings reported by anvil_nodeInfo. All
+// fields are zero if the node is not forking.
+type NodeForkConfig struct {
+	ForkURL         string
+	ForkBlockNumber uint64
+	// ForkRetryBackoff is the initial retry backoff in milliseconds.
+	ForkRetryBackoff uint64
+}
+
+// UnmarshalJSON decodes an anvil_nodeInfo result. Numeric fields are accepted
+// both as JSON numbers and as hex strings, since Anvil releases differ.
+func (n *NodeInfo) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		CurrentBlockNumber    quantity    `json:"currentBlockNumber"`
+		CurrentBlockTimestamp quantity    `json:"currentBlockTimestamp"`
+		CurrentBlockHash      common.Hash `json:"currentBlockHash"`
+		HardFork              string      `json:"hardFork"`
+		TransactionOrder      string      `json:"transactionOrder"`
+		Environment           struct {
+			BaseFee  quantity `json:"baseFee"`
+			ChainID  quantity `json:"chainId"`
+			GasLimit quantity `json:"gasLimit"`
+			GasPrice quantity `json:"gasPrice"`
+		} `json:"environment"`
+		ForkConfig struct {
+			ForkURL          string   `json:"forkUrl"`
+			ForkBlockNumber  quantity `json:"forkBlockNumber"`
+			ForkRetryBackoff quantity `json:"forkRetryBackoff"`
+		} `json:"forkConfig"`
+		Network string `json:"network"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*n = NodeInfo{
+		CurrentBlockNumber:    raw.CurrentBlockNumber.Uint64(),
+		CurrentBlockTimestamp: raw.CurrentBlockTimestamp.Uint64(),
+		CurrentBlockHash:      raw.CurrentBlockHash,
+		HardFork:              raw.HardFork,
+		TransactionOrder:      raw.TransactionOrder,
+		Environment: NodeEnvironment{
+			BaseFee:  raw.Environment.BaseFee.Big(),
+			ChainID:  raw.Environment.ChainID.Uint64(),
+			GasLimit: raw.Environment.GasLimit.Uint64(),
+			GasPrice: raw.Environment.GasPrice.Big(),
+		},
+		ForkConfig: NodeForkConfig{
+			ForkURL:          raw.ForkConfig.ForkURL,
+			ForkBlockNumber:  raw.ForkConfig.ForkBlockNumber.Uint64(),
+			ForkRetryBackoff: raw.ForkConfig.ForkRetryBackoff.Uint64(),
+		},
+		Network: raw.Network,
+	}
+	return nil
+}
+
+// Metadata is the result of anvil_metadata.
+type Metadata struct {
+	ClientVersion string
+	ChainID       uint64
+	// InstanceID changes every time the node is started or reset.
+	InstanceID        common.Hash
+	LatestBlockNumber uint64
+	LatestBlockHash   common.Hash
+	// ForkedNetwork is nil if the node is not forking.
+	ForkedNetwork *ForkedNetwork
+	// Snapshots maps the id of every live evm_snapshot to the block it was taken at.
+	Snapshots map[uint64]SnapshotInfo
+}
+
+// ForkedNetwork describes the network an Anvil node was forked from.
+type ForkedNetwork struct {
+	ChainID         uint64
+	ForkBlockNumber uint64
+	ForkBlockHash   common.Hash
+}
+
+// SnapshotInfo is the block an evm_snapshot was taken at.
+type SnapshotInfo struct {
+	BlockNumber uint64
+	BlockHash   common.Hash
+}
+
+// UnmarshalJSON decodes an anvil_metadata result.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ClientVersion     string      `json:"clientVersion"`
+		ChainID           quantity    `json:"chainId"`
+		InstanceID        common.Hash `json:"instanceId"`
+		LatestBlockNumber quantity    `json:"latestBlockNumber"`
+		LatestBlockHash   common.Hash `json:"latestBlockHash"`
+		ForkedNetwork     *struct {
+			ChainID         quantity    `json:"chainId"`
+			ForkBlockNumber quantity    `json:"forkBlockNumber"`
+			ForkBlockHash   common.Hash `json:"forkBlockHash"`
+		} `json:"forkedNetwork"`
+		Snapshots map[string][2]json.RawMessage `json:"snapshots"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = Metadata{
+		ClientVersion:     raw.ClientVersion,
+		ChainID:           raw.ChainID.Uint64(),
+		InstanceID:        raw.InstanceID,
+		LatestBlockNumber: raw.LatestBlockNumber.Uint64(),
+		LatestBlockHash:   raw.LatestBlockHash,
+		Snapshots:         make(map[uint64]SnapshotInfo, len(raw.Snapshots)),
+	}
+	if fork := raw.ForkedNetwork; fork != nil {
+		m.ForkedNetwork = &ForkedNetwork{
+			ChainID:         fork.ChainID.Uint64(),
+			ForkBlockNumber: fork.ForkBlockNumber.Uint64(),
+			ForkBlockHash:   fork.ForkBlockHash,
+		}
+	}
+	for key, entry := range raw.Snapshots {
+		var id, number quantity
+		if err := id.parse(key); err != nil {
+			return fmt.Errorf("invalid snapshot id %q: %w", key, err)
+		}
+		if err := json.Unmarshal(entry[0], &number); err != nil {
+			return fmt.Errorf("invalid block number for snapshot %s: %w", key, err)
+		}
+		var hash common.Hash
+		if err := json.Unmarshal(entry[1], &hash); err != nil {
+			return fmt.Errorf("invalid block hash for snapshot %s: %w", key, err)
+		}
+		m.Snapshots[id.Uint64()] = SnapshotInfo{BlockNumber: number.Uint64(), BlockHash: hash}
+	}
+	return nil
+}
+
+// quantity is a number that may be encoded as a JSON number, a decimal string
+// or a 0x-prefixed hex string. null decodes to zero.
+type quantity struct {
+	v big.Int
+}
+
+func (q *quantity) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		q.v.SetInt64(0)
+		return nil
+	}
+	if unquoted, ok := strings.CutPrefix(s, `"`); ok {
+		s = strings.TrimSuffix(unquoted, `"`)
+	}
+	return q.parse(s)
+}
+
+func (q *quantity) parse(s string) error {
+	base := 10
+	if hex, ok := strings.CutPrefix(s, "0x"); ok {
+		s, base = hex, 16
+	}
+	if _, ok := q.v.SetString(s, base); !ok || q.v.Sign() < 0 {
+		return fmt.Errorf("invalid quantity %q", s)
+	}
+	return nil
+}
+
+// Uint64 returns q truncated to 64 bits.
+func (q *quantity) Uint64() uint64 {
+	return q.v.Uint64()
+}
+
+// Big returns a copy of q.
+func (q *quantity) Big() *big.Int {
+	return new(big.Int).Set(&q.v)
+}
+
+// NodeInfo retrieves the configuration parameters for the currently running Anvil node.
+func (a Anvil) NodeInfo() (NodeInfo, error) {
+	return a.NodeInfoContext(context.Background())
+}
+
+// NodeInfoContext is like NodeInfo but honors ctx for cancellation and deadlines.
+func (a Anvil) NodeInfoContext(ctx context.Context) (NodeInfo, error) {
+	res, err := makeRequest[NodeInfo](ctx, a.rpcClient, "anvil_nodeInfo", []any{})
+	if err != nil {
+		return NodeInfo{}, err
+	}
+	return *res, nil
+}
+
+// Metadata returns information about the running node, including the client
+// version, the chain it was forked from and the live snapshots.
+func (a Anvil) Metadata() (Metadata, error) {
+	return a.MetadataContext(context.Background())
+}
+
+// MetadataContext is like Metadata but honors ctx for cancellation and deadlines.
+func (a Anvil) MetadataContext(ctx context.Context) (Metadata, error) {
+	res, err := makeRequest[Metadata](ctx, a.rpcClient, "anvil_metadata", []any{})
+	if err != nil {
+		return Metadata{}, err
+	}
+	return *res, nil
+}
@@ -0,0 +1,80 @@
+package anvil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestAnvil_NodeInfoDecode checks that anvil_nodeInfo decodes from both the
+// numeric and the hex encoding used by different Anvil releases.
+func TestAnvil_NodeInfoDecode(t *testing.T) {
+	bodies := map[string]string{
+		"numbers": `{
+			"currentBlockNumber": 5,
+			"currentBlockTimestamp": 1700000000,
+			"currentBlockHash": "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+			"hardFork": "prague",
+			"transactionOrder": "fees",
+			"environment": {"baseFee": 1000000000, "chainId": 31337, "gasLimit": 30000000, "gasPrice": 2000000000},
+			"forkConfig": {"forkUrl": "https://rpc.example", "forkBlockNumber": 19000000, "forkRetryBackoff": 1000},
+			"network": null
+		}`,
+		"hex": `{
+			"currentBlockNumber": "0x5",
+			"currentBlockTimestamp": "0x6553f100",
+			"currentBlockHash": "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+			"hardFork": "prague",
+			"transactionOrder": "fees",
+			"environment": {"baseFee": "0x3b9aca00", "chainId": "0x7a69", "gasLimit": "0x1c9c380", "gasPrice": "0x77359400"},
+			"forkConfig": {"forkUrl": "https://rpc.example", "forkBlockNumber": "0x121eac0", "forkRetryBackoff": "0x3e8"},
+			"network": null
+		}`,
+	}
+
+	for name, body := range bodies {
+		var info NodeInfo
+		if err := json.Unmarshal([]byte(body), &info); err != nil {
+			t.Fatalf("%s: decoding anvil_nodeInfo failed: %v", name, err)
+		}
+		if info.CurrentBlockNumber != 5 || info.CurrentBlockTimestamp != 1_700_000_000 {
+			t.Fatalf("%s: unexpected block: %+v", name, info)
+		}
+		env := info.Environment
+		if env.ChainID != 31337 || env.GasLimit != 30_000_000 || env.BaseFee.Int64() != 1e9 || env.GasPrice.Int64() != 2e9 {
+			t.Fatalf("%s: unexpected environment: %+v", name, env)
+		}
+		if info.ForkConfig.ForkBlockNumber != 19_000_000 || info.ForkConfig.ForkRetryBackoff != 1000 {
+			t.Fatalf("%s: unexpected fork config: %+v", name, info.ForkConfig)
+		}
+	}
+}
+
+// TestAnvil_MetadataDecode checks decoding of anvil_metadata.
+func TestAnvil_MetadataDecode(t *testing.T) {
+	body := `{
+		"clientVersion": "anvil/v1.3.0",
+		"chainId": 1,
+		"instanceId": "0x0000000000000000000000000000000000000000000000000000000000000001",
+		"latestBlockNumber": 19000001,
+		"latestBlockHash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+		"forkedNetwork": {
+			"chainId": 1,
+			"forkBlockNumber": 19000000,
+			"forkBlockHash": "0x0000000000000000000000000000000000000000000000000000000000000003"
+		},
+		"snapshots": {
+			"0x1": [19000001, "0x0000000000000000000000000000000000000000000000000000000000000002"]
+		}
+	}`
+
+	var meta Metadata
+	if err := json.Unmarshal([]byte(body), &meta); err != nil {
+		t.Fatalf("decoding anvil_metadata failed: %v", err)
+	}
+	if meta.ForkedNetwork == nil || meta.ForkedNetwork.ForkBlockNumber != 19_000_000 {
+		t.Fatalf("unexpected forked network: %+v", meta.ForkedNetwork)
+	}
+	if snap, ok := meta.Snapshots[1]; !ok || snap.BlockNumber != 19_000_001 {
+		t.Fatalf("unexpected snapshots: %+v", meta.Snapshots)
+	}
+}
@@ -0,0 +1,333 @@
+package anvil
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// OTSTraceEntry is a single call in the trace returned by ots_traceTransaction.
+type OTSTraceEntry struct {
+	// Type is the kind of call: CALL, STATICCALL, DELEGATECALL, CALLCODE,
+	// CREATE, CREATE2 or SELFDESTRUCT.
+	Type  string         `json:"type"`
+	Depth int            `json:"depth"`
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	// Value is the amount of ETH transferred, or nil for calls that cannot carry value.
+	Value  *hexutil.Big  `json:"value"`
+	Input  hexutil.Bytes `json:"input"`
+	Output hexutil.Bytes `json:"output"`
+}
+
+// OTSBlock is the slimmed down block returned by the ots_ block methods.
+// Transactions are omitted and replaced by their count.
+type OTSBlock struct {
+	Hash             common.Hash    `json:"hash"`
+	ParentHash       common.Hash    `json:"parentHash"`
+	Number           *hexutil.Big   `json:"number"`
+	Timestamp        hexutil.Uint64 `json:"timestamp"`
+	Miner            common.Address `json:"miner"`
+	Difficulty       *hexutil.Big   `json:"difficulty"`
+	GasLimit         hexutil.Uint64 `json:"gasLimit"`
+	GasUsed          hexutil.Uint64 `json:"gasUsed"`
+	BaseFeePerGas    *hexutil.Big   `json:"baseFeePerGas"`
+	ExtraData        hexutil.Bytes  `json:"extraData"`
+	Size             hexutil.Uint64 `json:"size"`
+	TransactionCount uint64         `json:"transactionCount"`
+}
+
+// OTSIssuance is the ETH issued by a block.
+type OTSIssuance struct {
+	BlockReward *hexutil.Big `json:"blockReward"`
+	UncleReward *hexutil.Big `json:"uncleReward"`
+	Issuance    *hexutil.Big `json:"issuance"`
+}
+
+// OTSBlockDetails is the result of ots_getBlockDetails.
+type OTSBlockDetails struct {
+	Block     OTSBlock     `json:"block"`
+	Issuance  OTSIssuance  `json:"issuance"`
+	TotalFees *hexutil.Big `json:"totalFees"`
+}
+
+// OTSReceipt is a transaction receipt as returned by the ots_ methods.
+// Logs are omitted by the search methods to keep responses small.
+type OTSReceipt struct {
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64  `json:"transactionIndex"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       *hexutil.Big    `json:"blockNumber"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Status            hexutil.Uint64  `json:"status"`
+	Type              hexutil.Uint64  `json:"type"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	Logs              []*types.Log    `json:"logs"`
+	// Timestamp is the timestamp of the including block. It is only set by
+	// the search methods.
+	Timestamp hexutil.Uint64 `json:"timestamp"`
+}
+
+// OTSBlockTransactions is the result of ots_getBlockTransactions.
+type OTSBlockTransactions struct {
+	FullBlock struct {
+		OTSBlock
+		Transactions []*RPCTransaction `json:"transactions"`
+	} `json:"fullblock"`
+	Receipts []OTSReceipt `json:"receipts"`
+}
+
+// OTSSearchResult is one page of ots_searchTransactionsBefore or
+// ots_searchTransactionsAfter. Txs and Receipts are index-aligned.
+type OTSSearchResult struct {
+	Txs       []*RPCTransaction `json:"txs"`
+	Receipts  []OTSReceipt      `json:"receipts"`
+	FirstPage bool              `json:"firstPage"`
+	LastPage  bool              `json:"lastPage"`
+}
+
+// OTSHistoryEntry is a transaction yielded by OTSAddressHistory.
+type OTSHistoryEntry struct {
+	Transaction *RPCTransaction
+	Receipt     OTSReceipt
+}
+
+// OTSGetApiLevel returns the Otterscan API level (simple version number).
+func (a Anvil) OTSGetApiLevel() (uint64, error) {
+	return a.OTSGetApiLevelContext(context.Background())
+}
+
+// OTSGetApiLevelContext is like OTSGetApiLevel but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetApiLevelContext(ctx context.Context) (uint64, error) {
+	res, err := makeRequest[uint64](ctx, a.rpcClient, "ots_getApiLevel", []any{})
+	if err != nil {
+		return 0, err
+	}
+	return *res, nil
+}
+
+// OTSInternalOperation is a simplified representation of an internal operation from ots_getInternalOperations.
+type OTSInternalOperation struct {
+	Type uint8          `json:"type"`
+	From common.Address `json:"from"`
+	To   common.Address `json:"to"`
+	// Value is the amount of ETH transferred, as a hex quantity string.
+	Value string `json:"value"`
+}
+
+// OTSGetInternalOperations returns internal ETH transfers for a transaction.
+func (a Anvil) OTSGetInternalOperations(txHash common.Hash) ([]OTSInternalOperation, error) {
+	return a.OTSGetInternalOperationsContext(context.Background(), txHash)
+}
+
+// OTSGetInternalOperationsContext is like OTSGetInternalOperations but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetInternalOperationsContext(ctx context.Context, txHash common.Hash) ([]OTSInternalOperation, error) {
+	res, err := makeRequest[[]OTSInternalOperation](ctx, a.rpcClient, "ots_getInternalOperations", []any{txHash.Hex()})
+	if err != nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+// OTSHasCode checks if an address contains deployed code at a specific block (or "latest").
+func (a Anvil) OTSHasCode(address common.Address, blockTag string) (bool, error) {
+	return a.OTSHasCodeContext(context.Background(), address, blockTag)
+}
+
+// OTSHasCodeContext is like OTSHasCode but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSHasCodeContext(ctx context.Context, address common.Address, blockTag string) (bool, error) {
+	res, err := makeRequest[bool](ctx, a.rpcClient, "ots_hasCode", []any{address.Hex(), blockTag})
+	if err != nil {
+		return false, err
+	}
+	return *res, nil
+}
+
+// OTSGetTransactionError returns the raw revert data for a transaction, or "0x" on success / no-reason failure.
+func (a Anvil) OTSGetTransactionError(txHash common.Hash) (string, error) {
+	return a.OTSGetTransactionErrorContext(context.Background(), txHash)
+}
+
+// OTSGetTransactionErrorContext is like OTSGetTransactionError but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetTransactionErrorContext(ctx context.Context, txHash common.Hash) (string, error) {
+	res, err := makeRequest[string](ctx, a.rpcClient, "ots_getTransactionError", []any{txHash.Hex()})
+	if err != nil {
+		return "", err
+	}
+	return *res, nil
+}
+
+// OTSTraceTransaction returns the call tree of a transaction, flattened in
+// execution order. Each entry's Depth gives its position in the tree.
+func (a Anvil) OTSTraceTransaction(txHash common.Hash) ([]OTSTraceEntry, error) {
+	return a.OTSTraceTransactionContext(context.Background(), txHash)
+}
+
+// OTSTraceTransactionContext is like OTSTraceTransaction but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSTraceTransactionContext(ctx context.Context, txHash common.Hash) ([]OTSTraceEntry, error) {
+	res, err := makeRequest[[]OTSTraceEntry](ctx, a.rpcClient, "ots_traceTransaction", []any{txHash.Hex()})
+	if err != nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+// OTSGetBlockDetails returns a tailored block object for a given block number,
+// including its issuance and the total fees paid in the block.
+func (a Anvil) OTSGetBlockDetails(blockNumber uint64) (OTSBlockDetails, error) {
+	return a.OTSGetBlockDetailsContext(context.Background(), blockNumber)
+}
+
+// OTSGetBlockDetailsContext is like OTSGetBlockDetails but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetBlockDetailsContext(ctx context.Context, blockNumber uint64) (OTSBlockDetails, error) {
+	res, err := makeRequest[OTSBlockDetails](ctx, a.rpcClient, "ots_getBlockDetails", []any{toHexQuantityUint64(blockNumber)})
+	if err != nil {
+		return OTSBlockDetails{}, err
+	}
+	return *res, nil
+}
+
+// OTSGetBlockTransactions returns the first page of the transactions and receipts of a block.
+// Use OTSGetBlockTransactionsPage to fetch later pages.
+func (a Anvil) OTSGetBlockTransactions(blockNumber uint64, pageSize uint64) (OTSBlockTransactions, error) {
+	return a.OTSGetBlockTransactionsContext(context.Background(), blockNumber, pageSize)
+}
+
+// OTSGetBlockTransactionsContext is like OTSGetBlockTransactions but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetBlockTransactionsContext(ctx context.Context, blockNumber uint64, pageSize uint64) (OTSBlockTransactions, error) {
+	return a.OTSGetBlockTransactionsPageContext(ctx, blockNumber, 0, pageSize)
+}
+
+// OTSGetBlockTransactionsPage returns one page of the transactions and receipts of a block.
+// Pages are numbered from 0.
+func (a Anvil) OTSGetBlockTransactionsPage(blockNumber uint64, pageNumber uint64, pageSize uint64) (OTSBlockTransactions, error) {
+	return a.OTSGetBlockTransactionsPageContext(context.Background(), blockNumber, pageNumber, pageSize)
+}
+
+// OTSGetBlockTransactionsPageContext is like OTSGetBlockTransactionsPage but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetBlockTransactionsPageContext(ctx context.Context, blockNumber uint64, pageNumber uint64, pageSize uint64) (OTSBlockTransactions, error) {
+	params := []any{toHexQuantityUint64(blockNumber), pageNumber, pageSize}
+	res, err := makeRequest[OTSBlockTransactions](ctx, a.rpcClient, "ots_getBlockTransactions", params)
+	if err != nil {
+		return OTSBlockTransactions{}, err
+	}
+	return *res, nil
+}
+
+// OTSSearchTransactionsBefore searches paginated inbound/outbound/internal transactions for an address before a block.
+// Results are ordered from newest to oldest. A blockNumber of 0 starts from the latest block.
+func (a Anvil) OTSSearchTransactionsBefore(address common.Address, blockNumber uint64, pageSize uint64) (OTSSearchResult, error) {
+	return a.OTSSearchTransactionsBeforeContext(context.Background(), address, blockNumber, pageSize)
+}
+
+// OTSSearchTransactionsBeforeContext is like OTSSearchTransactionsBefore but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSSearchTransactionsBeforeContext(ctx context.Context, address common.Address, blockNumber uint64, pageSize uint64) (OTSSearchResult, error) {
+	params := []any{address.Hex(), blockNumber, pageSize}
+	res, err := makeRequest[OTSSearchResult](ctx, a.rpcClient, "ots_searchTransactionsBefore", params)
+	if err != nil {
+		return OTSSearchResult{}, err
+	}
+	return *res, nil
+}
+
+// OTSSearchTransactionsAfter searches paginated inbound/outbound/internal transactions for an address after a block.
+// Results are ordered from newest to oldest.
+func (a Anvil) OTSSearchTransactionsAfter(address common.Address, blockNumber uint64, pageSize uint64) (OTSSearchResult, error) {
+	return a.OTSSearchTransactionsAfterContext(context.Background(), address, blockNumber, pageSize)
+}
+
+// OTSSearchTransactionsAfterContext is like OTSSearchTransactionsAfter but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSSearchTransactionsAfterContext(ctx context.Context, address common.Address, blockNumber uint64, pageSize uint64) (OTSSearchResult, error) {
+	params := []any{address.Hex(), blockNumber, pageSize}
+	res, err := makeRequest[OTSSearchResult](ctx, a.rpcClient, "ots_searchTransactionsAfter", params)
+	if err != nil {
+		return OTSSearchResult{}, err
+	}
+	return *res, nil
+}
+
+// OTSAddressHistory walks every page of ots_searchTransactionsBefore for an
+// address, yielding its transactions from newest to oldest. Iteration stops
+// at the first error, which is yielded along with a zero OTSHistoryEntry.
+func (a Anvil) OTSAddressHistory(address common.Address, pageSize uint64) iter.Seq2[OTSHistoryEntry, error] {
+	return a.OTSAddressHistoryContext(context.Background(), address, pageSize)
+}
+
+// OTSAddressHistoryContext is like OTSAddressHistory but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSAddressHistoryContext(ctx context.Context, address common.Address, pageSize uint64) iter.Seq2[OTSHistoryEntry, error] {
+	return func(yield func(OTSHistoryEntry, error) bool) {
+		var cursor uint64 // 0 starts from the latest block
+		for {
+			page, err := a.OTSSearchTransactionsBeforeContext(ctx, address, cursor, pageSize)
+			if err != nil {
+				yield(OTSHistoryEntry{}, err)
+				return
+			}
+			if len(page.Txs) != len(page.Receipts) {
+				yield(OTSHistoryEntry{}, fmt.Errorf("ots_searchTransactionsBefore returned %d txs but %d receipts", len(page.Txs), len(page.Receipts)))
+				return
+			}
+
+			for i, tx := range page.Txs {
+				if !yield(OTSHistoryEntry{Transaction: tx, Receipt: page.Receipts[i]}, nil) {
+					return
+				}
+			}
+
+			// A page never splits a block, so the oldest block on this page
+			// is where the next search continues from.
+			if page.LastPage || len(page.Txs) == 0 {
+				return
+			}
+			last := page.Receipts[len(page.Receipts)-1].BlockNumber
+			if last == nil || last.ToInt().Uint64() == 0 {
+				return
+			}
+			cursor = last.ToInt().Uint64()
+		}
+	}
+}
+
+// OTSGetTransactionBySenderAndNonce returns the transaction hash for a given sender and nonce, or "" if not found.
+func (a Anvil) OTSGetTransactionBySenderAndNonce(sender common.Address, nonce uint64) (string, error) {
+	return a.OTSGetTransactionBySenderAndNonceContext(context.Background(), sender, nonce)
+}
+
+// OTSGetTransactionBySenderAndNonceContext is like OTSGetTransactionBySenderAndNonce but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetTransactionBySenderAndNonceContext(ctx context.Context, sender common.Address, nonce uint64) (string, error) {
+	params := []any{sender.Hex(), nonce}
+	res, err := makeRequest[string](ctx, a.rpcClient, "ots_getTransactionBySenderAndNonce", params)
+	if err != nil {
+		return "", err
+	}
+	return *res, nil
+}
+
+// OTSContractCreator is the result of ots_getContractCreator.
+type OTSContractCreator struct {
+	Hash    common.Hash    `json:"hash"`
+	Creator common.Address `json:"creator"`
+}
+
+// OTSGetContractCreator returns the tx hash and creator address that deployed a contract,
+// or (nil, nil) if the address is not a contract.
+func (a Anvil) OTSGetContractCreator(address common.Address) (*OTSContractCreator, error) {
+	return a.OTSGetContractCreatorContext(context.Background(), address)
+}
+
+// OTSGetContractCreatorContext is like OTSGetContractCreator but honors ctx for cancellation and deadlines.
+func (a Anvil) OTSGetContractCreatorContext(ctx context.Context, address common.Address) (*OTSContractCreator, error) {
+	res, err := makeRequest[*OTSContractCreator](ctx, a.rpcClient, "ots_getContractCreator", []any{address.Hex()})
+	if err != nil {
+		return nil, err
+	}
+	return *res, nil
+}
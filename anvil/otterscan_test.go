@@ -0,0 +1,96 @@
+package anvil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestAnvil_OTSBlockDetailsDecode checks decoding of ots_getBlockDetails.
+func TestAnvil_OTSBlockDetailsDecode(t *testing.T) {
+	body := `{
+		"block": {
+			"hash": "0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6",
+			"parentHash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+			"number": "0x1",
+			"timestamp": "0x55ba4224",
+			"miner": "0x05a56e2d52c817161883f50c441c3228cfe54d9f",
+			"difficulty": "0x3ff800000",
+			"gasLimit": "0x1388",
+			"gasUsed": "0x0",
+			"baseFeePerGas": null,
+			"extraData": "0x476574682f76312e302e302f6c696e75782f676f312e342e32",
+			"size": "0x219",
+			"logsBloom": null,
+			"transactionCount": 0
+		},
+		"issuance": {
+			"blockReward": "0x4563918244f40000",
+			"uncleReward": "0x0",
+			"issuance": "0x4563918244f40000"
+		},
+		"totalFees": "0x0"
+	}`
+
+	var details OTSBlockDetails
+	if err := json.Unmarshal([]byte(body), &details); err != nil {
+		t.Fatalf("decoding ots_getBlockDetails failed: %v", err)
+	}
+	if details.Block.Number.ToInt().Uint64() != 1 || details.Block.GasLimit != 5000 {
+		t.Fatalf("unexpected block: %+v", details.Block)
+	}
+	if details.Issuance.BlockReward.ToInt().String() != "5000000000000000000" {
+		t.Fatalf("unexpected issuance: %+v", details.Issuance)
+	}
+}
+
+// TestAnvil_OTSAddressHistory checks that the history iterator walks every page.
+func TestAnvil_OTSAddressHistory(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	page := func(lastPage bool, blocks ...uint64) map[string]any {
+		var txs, receipts []any
+		for i, block := range blocks {
+			txs = append(txs, testTxJSON(key, uint64(i), to))
+			receipts = append(receipts, map[string]any{
+				"blockNumber": toHexQuantityUint64(block),
+				"status":      "0x1",
+			})
+		}
+		return map[string]any{"txs": txs, "receipts": receipts, "firstPage": false, "lastPage": lastPage}
+	}
+
+	var cursors []uint64
+	anvl := stubAnvil(t, func(method string, params []json.RawMessage) any {
+		var cursor uint64
+		json.Unmarshal(params[1], &cursor)
+		cursors = append(cursors, cursor)
+
+		switch cursor {
+		case 0:
+			return page(false, 10, 10, 8)
+		case 8:
+			return page(true, 3)
+		}
+		t.Errorf("unexpected cursor %d", cursor)
+		return nil
+	})
+
+	var blocks []uint64
+	for entry, err := range anvl.OTSAddressHistory(account0, 3) {
+		if err != nil {
+			t.Fatalf("OTSAddressHistory failed: %v", err)
+		}
+		blocks = append(blocks, entry.Receipt.BlockNumber.ToInt().Uint64())
+	}
+
+	if len(blocks) != 4 || blocks[3] != 3 {
+		t.Fatalf("unexpected history: %v", blocks)
+	}
+	if len(cursors) != 2 {
+		t.Fatalf("unexpected number of pages: %v", cursors)
+	}
+}
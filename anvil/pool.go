@@ -0,0 +1,301 @@
+package anvil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrPoolClosed is returned by Pool.Acquire once the pool has been closed.
+	ErrPoolClosed = errors.New("anvil pool closed")
+	// ErrPoolEmpty is returned by Pool.Acquire once every instance has been
+	// removed from the pool after a failed reset.
+	ErrPoolEmpty = errors.New("anvil pool has no instances left")
+)
+
+// PoolReset selects how a Pool restores an instance when it is released.
+type PoolReset int
+
+const (
+	// PoolResetSnapshot reverts to a snapshot taken right after startup.
+	// This is fast and keeps fork state that was already fetched.
+	PoolResetSnapshot PoolReset = iota
+	// PoolResetFork calls anvil_reset, re-forking from the block the instance
+	// started at, or restarting from genesis when not forking. This also
+	// discards cached fork state and anything else a snapshot would keep,
+	// such as impersonated accounts and mining settings.
+	//
+	// anvil_reset only takes a fork URL and block number, so the reset
+	// re-forks from the URL and the block reported by anvil_nodeInfo at
+	// startup. Settings made with SetForkHeaders, SetForkChainID,
+	// SetForkTransactionHash, SetRetries, SetForkRetryBackoff, SetTimeout and
+	// SetComputeUnitsPerSecond are not part of the request and may not
+	// survive the first Release. Use PoolResetSnapshot when they matter.
+	PoolResetFork
+)
+
+// Pool runs several independent Anvil instances started from the same
+// Config and hands them out to one user at a time.
+type Pool struct {
+	reset PoolReset
+	idle  chan *Anvil
+	done  chan struct{}
+	// empty is closed when the last instance is removed from the pool.
+	empty chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	members map[*Anvil]*poolMember
+}
+
+// poolMember is the state a Pool keeps for each instance.
+type poolMember struct {
+	acquired bool
+	// snapshot is the snapshot to revert to with PoolResetSnapshot.
+	snapshot string
+	// fork is the anvil_reset parameter for PoolResetFork, nil if not forking.
+	fork any
+}
+
+// NewPool starts size instances configured with config and waits until all
+// of them are ready. Every instance listens on its own ephemeral port, so
+// config must not set a fixed port, an IPC endpoint or files that instances
+// would write to concurrently (state, dump state and config out paths).
+//
+// ctx bounds the setup of the instances after they started; each start is
+// bounded by the startup timeout of config.
+func NewPool(ctx context.Context, config *Config, size int, reset PoolReset) (*Pool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("pool size must be positive, got %d", size)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if err := checkPoolConfig(config); err != nil {
+		return nil, err
+	}
+
+	p := &Pool{
+		reset:   reset,
+		idle:    make(chan *Anvil, size),
+		done:    make(chan struct{}),
+		empty:   make(chan struct{}),
+		members: make(map[*Anvil]*poolMember, size),
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for range size {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			a, member, err := p.start(ctx, config)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			p.members[a] = member
+			p.idle <- a
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		p.Close()
+		return nil, errors.Join(errs...)
+	}
+	return p, nil
+}
+
+// checkPoolConfig rejects settings that cannot be shared by several instances.
+func checkPoolConfig(config *Config) error {
+	var errs []error
+	if config.port != 0 {
+		errs = append(errs, errors.New("pooled instances cannot share a fixed port"))
+	}
+	if config.ipc {
+		errs = append(errs, errors.New("pooled instances cannot share an IPC endpoint"))
+	}
+	for _, path := range []struct{ setter, value string }{
+		{"SetStatePath", config.statePath},
+		{"SetDumpStatePath", config.dumpStatePath},
+		{"SetConfigOut", config.configOut},
+	} {
+		if path.value != "" {
+			errs = append(errs, fmt.Errorf("pooled instances cannot share the file set with %s", path.setter))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// start starts one instance and records what is needed to reset it.
+func (p *Pool) start(ctx context.Context, config *Config) (*Anvil, *poolMember, error) {
+	a, err := NewWithConfig(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	member := &poolMember{}
+	switch p.reset {
+	case PoolResetSnapshot:
+		member.snapshot, err = a.EvmSnapshotContext(ctx)
+	case PoolResetFork:
+		if config.forkURL != "" {
+			var info NodeInfo
+			info, err = a.NodeInfoContext(ctx)
+			member.fork = map[string]any{
+				"forking": map[string]any{
+					"jsonRpcUrl":  config.forkURL,
+					"blockNumber": info.ForkConfig.ForkBlockNumber,
+				},
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown pool reset mode %d", p.reset)
+	}
+	if err != nil {
+		a.Close()
+		return nil, nil, err
+	}
+	return &a, member, nil
+}
+
+// Acquire takes an idle instance from the pool, waiting until one is
+// released if all are in use. It returns ErrPoolClosed once the pool is
+// closed, ErrPoolEmpty once no instance is left, or ctx.Err() if ctx is done
+// first.
+func (p *Pool) Acquire(ctx context.Context) (*Anvil, error) {
+	select {
+	case <-p.done:
+		return nil, ErrPoolClosed
+	default:
+	}
+
+	select {
+	case a := <-p.idle:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.closed {
+			return nil, ErrPoolClosed
+		}
+		p.members[a].acquired = true
+		return a, nil
+	case <-p.done:
+		return nil, ErrPoolClosed
+	case <-p.empty:
+		return nil, ErrPoolEmpty
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Release resets a and returns it to the pool. If the reset fails, the
+// instance is closed and removed from the pool, and the error is returned.
+// Removing the last instance makes pending and later calls to Acquire return
+// ErrPoolEmpty.
+func (p *Pool) Release(a *Anvil) error {
+	return p.ReleaseContext(context.Background(), a)
+}
+
+// ReleaseContext is like Release but honors ctx for cancellation and
+// deadlines. An instance whose reset is cut short by ctx is removed from the
+// pool like one whose reset failed.
+func (p *Pool) ReleaseContext(ctx context.Context, a *Anvil) error {
+	p.mu.Lock()
+	member, ok := p.members[a]
+	if !ok || !member.acquired {
+		p.mu.Unlock()
+		return errors.New("instance was not acquired from this pool")
+	}
+	member.acquired = false
+	closed := p.closed
+	p.mu.Unlock()
+
+	if closed {
+		return nil
+	}
+
+	if err := p.resetMember(ctx, a, member); err != nil {
+		p.mu.Lock()
+		delete(p.members, a)
+		if len(p.members) == 0 {
+			close(p.empty)
+		}
+		p.mu.Unlock()
+		a.Close()
+		return fmt.Errorf("error resetting pooled instance: %w", err)
+	}
+
+	p.idle <- a
+	return nil
+}
+
+// resetMember restores a to the state it was in when the pool started it.
+func (p *Pool) resetMember(ctx context.Context, a *Anvil, member *poolMember) error {
+	if p.reset == PoolResetFork {
+		return a.ResetContext(ctx, member.fork)
+	}
+
+	if err := a.revertSnapshot(ctx, member.snapshot); err != nil {
+		return err
+	}
+	// Reverting consumes the snapshot, so take a fresh one for next time.
+	snapshot, err := a.EvmSnapshotContext(ctx)
+	if err != nil {
+		return err
+	}
+	member.snapshot = snapshot
+	return nil
+}
+
+// Len returns the number of instances in the pool, whether idle or acquired.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.members)
+}
+
+// Close stops every instance in the pool, including acquired ones. Pending
+// and later calls to Acquire return ErrPoolClosed. Close is safe to call more
+// than once.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	members := make([]*Anvil, 0, len(p.members))
+	for a := range p.members {
+		members = append(members, a)
+	}
+	p.mu.Unlock()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, a := range members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := a.Close(); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
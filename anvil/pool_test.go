@@ -0,0 +1,157 @@
+package anvil
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestAnvil_PoolConfig checks that settings instances cannot share are rejected.
+func TestAnvil_PoolConfig(t *testing.T) {
+	configs := map[string]*Config{
+		"port":       NewConfig().SetPort(8545),
+		"ipc":        NewConfig().SetIPC(true, ""),
+		"state":      NewConfig().SetStatePath("state.json"),
+		"config out": NewConfig().SetConfigOut("out.json"),
+	}
+	for name, config := range configs {
+		if _, err := NewPool(context.Background(), config, 2, PoolResetSnapshot); err == nil {
+			t.Fatalf("%s: NewPool accepted a config instances cannot share", name)
+		}
+	}
+}
+
+// TestAnvil_PoolAcquireRelease checks hand-out, snapshot reset and shutdown against stub instances.
+func TestAnvil_PoolAcquireRelease(t *testing.T) {
+	stub := &snapshotStub{}
+	p := &Pool{
+		reset:   PoolResetSnapshot,
+		idle:    make(chan *Anvil, 1),
+		done:    make(chan struct{}),
+		empty:   make(chan struct{}),
+		members: map[*Anvil]*poolMember{},
+	}
+	a := stubAnvil(t, stub.handle)
+	snapshot, _ := a.EvmSnapshot()
+	p.members[&a] = &poolMember{snapshot: snapshot}
+	p.idle <- &a
+
+	got, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := p.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire on an exhausted pool returned %v", err)
+	}
+
+	if err := p.Release(got); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if !slices.Equal(stub.reverts, []string{snapshot}) || len(stub.live) != 1 {
+		t.Fatalf("unexpected reverts %v, live %v", stub.reverts, stub.live)
+	}
+	if err := p.Release(got); err == nil {
+		t.Fatalf("Release accepted an instance twice")
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := p.Acquire(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("Acquire after Close returned %v", err)
+	}
+}
+
+// TestAnvil_PoolResetFailure checks that removing the last instance after a
+// failed reset wakes up waiting callers of Acquire.
+func TestAnvil_PoolResetFailure(t *testing.T) {
+	stub := &snapshotStub{}
+	p := &Pool{
+		reset:   PoolResetSnapshot,
+		idle:    make(chan *Anvil, 1),
+		done:    make(chan struct{}),
+		empty:   make(chan struct{}),
+		members: map[*Anvil]*poolMember{},
+	}
+	a := stubAnvil(t, stub.handle)
+	// The stub has no such snapshot, so reverting to it fails.
+	p.members[&a] = &poolMember{snapshot: "0x99"}
+	p.idle <- &a
+
+	got, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := p.Acquire(context.Background())
+		waiter <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.ReleaseContext(ctx, got); err == nil {
+		t.Fatalf("ReleaseContext succeeded although the reset failed")
+	}
+	if p.Len() != 0 {
+		t.Fatalf("pool still has %d instances after a failed reset", p.Len())
+	}
+
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, ErrPoolEmpty) {
+			t.Fatalf("waiting Acquire returned %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("waiting Acquire was not woken up")
+	}
+	if _, err := p.Acquire(context.Background()); !errors.Is(err, ErrPoolEmpty) {
+		t.Fatalf("Acquire on an empty pool returned %v", err)
+	}
+}
+
+// TestAnvil_Pool starts a small pool and checks that released instances are reset.
+func TestAnvil_Pool(t *testing.T) {
+	p, err := NewPool(context.Background(), NewConfig(), 2, PoolResetSnapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	first, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	second, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if first.HttpUrl() == second.HttpUrl() {
+		t.Fatalf("pooled instances share %s", first.HttpUrl())
+	}
+
+	if err := first.SetNonce(account0, 7); err != nil {
+		t.Fatalf("SetNonce failed: %v", err)
+	}
+	if err := p.Release(first); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+
+	again, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	nonce, err := again.EthClient().NonceAt(context.Background(), account0, nil)
+	if err != nil {
+		t.Fatalf("NonceAt failed: %v", err)
+	}
+	if nonce != 0 {
+		t.Fatalf("released instance kept nonce %d", nonce)
+	}
+}
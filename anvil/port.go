@@ -0,0 +1,43 @@
+package anvil
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+)
+
+// listenAddrRe matches the line Anvil prints once its server is bound,
+// e.g. "Listening on 127.0.0.1:8545".
+var listenAddrRe = regexp.MustCompile(`Listening on (\S+:\d+)`)
+
+// parseListenPort extracts the port from Anvil's "Listening on" startup line.
+func parseListenPort(line string) (uint, bool) {
+	m := listenAddrRe.FindStringSubmatch(line)
+	if m == nil {
+		return 0, false
+	}
+
+	_, portStr, err := net.SplitHostPort(m[1])
+	if err != nil {
+		return 0, false
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil || port == 0 {
+		return 0, false
+	}
+
+	return uint(port), true
+}
+
+// freePort asks the kernel for a free TCP port on the loopback interface.
+func freePort() (uint, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("error allocating a free port: %v", err)
+	}
+	defer l.Close()
+
+	return uint(l.Addr().(*net.TCPAddr).Port), nil
+}
@@ -0,0 +1,25 @@
+package anvil
+
+import "testing"
+
+// TestAnvil_ParseListenPort checks port detection from Anvil's startup output.
+func TestAnvil_ParseListenPort(t *testing.T) {
+	tests := []struct {
+		line string
+		port uint
+		ok   bool
+	}{
+		{"Listening on 127.0.0.1:8545", 8545, true},
+		{"Listening on [::1]:40123", 40123, true},
+		{`{"level":"INFO","fields":{"message":"Listening on 0.0.0.0:51234"}}`, 51234, true},
+		{"Listening on 127.0.0.1:0", 0, false},
+		{"Base Fee", 0, false},
+	}
+
+	for _, tt := range tests {
+		port, ok := parseListenPort(tt.line)
+		if port != tt.port || ok != tt.ok {
+			t.Errorf("parseListenPort(%q) = %d, %v; want %d, %v", tt.line, port, ok, tt.port, tt.ok)
+		}
+	}
+}
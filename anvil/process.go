@@ -0,0 +1,119 @@
+package anvil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// process tracks a running anvil child process. The process is reaped by a
+// background goroutine as soon as it exits, so done can be used both to wait
+// for a graceful shutdown and to notice unexpected exits.
+type process struct {
+	cmd *exec.Cmd
+
+	// done is closed once the process has exited and been reaped.
+	done chan struct{}
+	// waitErr is the result of cmd.Wait. Only valid after done is closed.
+	waitErr error
+
+	stopOnce sync.Once
+	stopErr  error
+}
+
+// startProcess starts cmd and begins waiting on it in the background.
+func startProcess(cmd *exec.Cmd) (*process, error) {
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &process{
+		cmd:  cmd,
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		p.waitErr = cmd.Wait()
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+// stop asks the process to exit with SIGTERM and waits up to grace for it to
+// do so, escalating to SIGKILL afterwards. It is safe to call stop multiple
+// times; only the first call has any effect and later calls return its result.
+func (p *process) stop(grace time.Duration) error {
+	p.stopOnce.Do(func() {
+		p.stopErr = p.terminate(grace)
+	})
+	return p.stopErr
+}
+
+func (p *process) terminate(grace time.Duration) error {
+	select {
+	case <-p.done:
+		// Exited on its own before we asked it to.
+		return p.exitErr(false)
+	default:
+	}
+
+	err := p.cmd.Process.Signal(syscall.SIGTERM)
+	if err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("error closing Anvil: %v", err)
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-p.done:
+		return p.exitErr(true)
+	case <-timer.C:
+	}
+
+	p.cmd.Process.Kill()
+	<-p.done
+
+	return fmt.Errorf("Anvil did not exit within %s and was killed", grace)
+}
+
+// kill terminates the process immediately and waits for it to be reaped.
+func (p *process) kill() {
+	p.stopOnce.Do(func() {
+		p.cmd.Process.Kill()
+		<-p.done
+	})
+}
+
+// exitErr reports how the process exited. A process stopped by the SIGTERM we
+// sent is considered to have exited cleanly.
+func (p *process) exitErr(signaled bool) error {
+	if p.waitErr == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(p.waitErr, &exitErr) && signaled {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok &&
+			status.Signaled() && status.Signal() == syscall.SIGTERM {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Anvil exited with error: %w", p.waitErr)
+}
+
+// state returns the exit state of the process, or nil if it is still running.
+func (p *process) state() *os.ProcessState {
+	select {
+	case <-p.done:
+		return p.cmd.ProcessState
+	default:
+		return nil
+	}
+}
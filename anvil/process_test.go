@@ -0,0 +1,43 @@
+package anvil
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// TestAnvil_ProcessStop checks that stop waits for and reaps the process, and is idempotent.
+func TestAnvil_ProcessStop(t *testing.T) {
+	proc, err := startProcess(exec.Command("sleep", "60"))
+	if err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+
+	if err := proc.stop(5 * time.Second); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	if proc.state() == nil {
+		t.Fatalf("process was not reaped")
+	}
+	if err := proc.stop(5 * time.Second); err != nil {
+		t.Fatalf("second stop failed: %v", err)
+	}
+}
+
+// TestAnvil_ProcessStopEscalates checks that a process ignoring SIGTERM is killed after the grace period.
+func TestAnvil_ProcessStopEscalates(t *testing.T) {
+	proc, err := startProcess(exec.Command("sh", "-c", `trap "" TERM; while :; do sleep 0.1; done`))
+	if err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+
+	// Give the shell a moment to install its trap.
+	time.Sleep(200 * time.Millisecond)
+
+	if err := proc.stop(200 * time.Millisecond); err == nil {
+		t.Fatalf("expected an error for a process that had to be killed")
+	}
+	if proc.state() == nil {
+		t.Fatalf("process was not reaped")
+	}
+}
@@ -0,0 +1,175 @@
+package anvil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReorgTransaction is a transaction to include in a replacement block during a
+// reorg. Exactly one of Tx and Msg must be set.
+type ReorgTransaction struct {
+	// Tx is a signed transaction, included as is.
+	Tx *types.Transaction
+	// Msg is an unsigned transaction sent from Msg.From, which Anvil
+	// includes without a signature check as if the sender were impersonated.
+	Msg *ethereum.CallMsg
+}
+
+// ReorgResult describes the blocks replaced by SimulateReorg.
+type ReorgResult struct {
+	// Ancestor is the number of the last block shared by the old and new chain.
+	Ancestor uint64
+	// OldHashes are the hashes of the orphaned blocks, starting at Ancestor+1.
+	OldHashes []common.Hash
+	// NewHashes are the hashes of the replacement blocks at the same heights.
+	NewHashes []common.Hash
+}
+
+// Reorg replaces the last depth blocks with depth new blocks. blocks[i] holds
+// the transactions to include in the i-th replacement block; it may be shorter
+// than depth, in which case the remaining blocks are empty.
+func (a Anvil) Reorg(depth uint64, blocks [][]ReorgTransaction) error {
+	return a.ReorgContext(context.Background(), depth, blocks)
+}
+
+// ReorgContext is like Reorg but honors ctx for cancellation and deadlines.
+func (a Anvil) ReorgContext(ctx context.Context, depth uint64, blocks [][]ReorgTransaction) error {
+	if uint64(len(blocks)) > depth {
+		return fmt.Errorf("got transactions for %d blocks, but reorg depth is %d", len(blocks), depth)
+	}
+
+	pairs := []any{}
+	for i, txs := range blocks {
+		for _, tx := range txs {
+			data, err := tx.encode()
+			if err != nil {
+				return err
+			}
+			pairs = append(pairs, []any{data, i})
+		}
+	}
+
+	opts := map[string]any{"depth": depth, "txBlockPairs": pairs}
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_reorg", []any{opts})
+	return err
+}
+
+// encode returns the transaction in the form anvil_reorg expects: raw bytes
+// for signed transactions and a transaction request object otherwise.
+func (tx ReorgTransaction) encode() (any, error) {
+	switch {
+	case tx.Tx != nil && tx.Msg != nil:
+		return nil, fmt.Errorf("reorg transaction must set either Tx or Msg, not both")
+	case tx.Tx != nil:
+		raw, err := tx.Tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("error encoding reorg transaction: %w", err)
+		}
+		return hexutil.Bytes(raw), nil
+	case tx.Msg != nil:
+		return callMsgToRequest(tx.Msg), nil
+	default:
+		return nil, fmt.Errorf("reorg transaction must set Tx or Msg")
+	}
+}
+
+// callMsgToRequest converts msg into a JSON-RPC transaction request object.
+func callMsgToRequest(msg *ethereum.CallMsg) map[string]any {
+	req := map[string]any{"from": msg.From}
+	if msg.To != nil {
+		req["to"] = msg.To
+	}
+	if len(msg.Data) > 0 {
+		req["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		req["value"] = toHexQuantityBig(msg.Value)
+	}
+	if msg.Gas != 0 {
+		req["gas"] = toHexQuantityUint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		req["gasPrice"] = toHexQuantityBig(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		req["maxFeePerGas"] = toHexQuantityBig(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		req["maxPriorityFeePerGas"] = toHexQuantityBig(msg.GasTipCap)
+	}
+	if msg.AccessList != nil {
+		req["accessList"] = msg.AccessList
+	}
+	return req
+}
+
+// Rollback removes the last depth blocks from the chain without replacing
+// them. If depth is 0, Anvil rolls back a single block.
+func (a Anvil) Rollback(depth uint64) error {
+	return a.RollbackContext(context.Background(), depth)
+}
+
+// RollbackContext is like Rollback but honors ctx for cancellation and deadlines.
+func (a Anvil) RollbackContext(ctx context.Context, depth uint64) error {
+	params := []any{}
+	if depth != 0 {
+		params = append(params, depth)
+	}
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_rollback", params)
+	return err
+}
+
+// SimulateReorg performs a Reorg and reports the hashes of the blocks that
+// were replaced, so tests can check that consumers noticed the reorg.
+func (a Anvil) SimulateReorg(depth uint64, blocks [][]ReorgTransaction) (ReorgResult, error) {
+	return a.SimulateReorgContext(context.Background(), depth, blocks)
+}
+
+// SimulateReorgContext is like SimulateReorg but honors ctx for cancellation and deadlines.
+func (a Anvil) SimulateReorgContext(ctx context.Context, depth uint64, blocks [][]ReorgTransaction) (ReorgResult, error) {
+	head, err := a.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return ReorgResult{}, fmt.Errorf("error fetching head block: %w", err)
+	}
+	if depth == 0 || depth > head {
+		return ReorgResult{}, fmt.Errorf("reorg depth must be between 1 and the head block %d, got %d", head, depth)
+	}
+
+	result := ReorgResult{Ancestor: head - depth}
+	if result.OldHashes, err = a.blockHashes(ctx, result.Ancestor+1, head); err != nil {
+		return ReorgResult{}, err
+	}
+	if err := a.ReorgContext(ctx, depth, blocks); err != nil {
+		return ReorgResult{}, err
+	}
+	if result.NewHashes, err = a.blockHashes(ctx, result.Ancestor+1, head); err != nil {
+		return ReorgResult{}, err
+	}
+	return result, nil
+}
+
+// blockHashes returns the hashes of the blocks from..to, inclusive. The hashes
+// are taken from the node rather than recomputed from the header, which would
+// be wrong for chains whose headers go-ethereum can't represent, such as
+// Optimism.
+func (a Anvil) blockHashes(ctx context.Context, from, to uint64) ([]common.Hash, error) {
+	hashes := make([]common.Hash, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		block, err := makeRequest[struct {
+			Hash common.Hash `json:"hash"`
+		}](ctx, a.rpcClient, "eth_getBlockByNumber", []any{toHexQuantityUint64(n), false})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching block %d: %w", n, err)
+		}
+		if block.Hash == (common.Hash{}) {
+			return nil, fmt.Errorf("block %d not found", n)
+		}
+		hashes = append(hashes, block.Hash)
+	}
+	return hashes, nil
+}
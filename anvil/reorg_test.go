@@ -0,0 +1,150 @@
+package anvil
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestAnvil_ReorgParams checks the anvil_reorg and anvil_rollback request encoding.
+func TestAnvil_ReorgParams(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	signed, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(31337)), &types.DynamicFeeTx{
+		ChainID: big.NewInt(31337), Gas: 21000, GasFeeCap: big.NewInt(1e9), To: &to,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := map[string][]json.RawMessage{}
+	anvl := stubAnvil(t, func(method string, params []json.RawMessage) any {
+		calls[method] = params
+		return nil
+	})
+
+	blocks := [][]ReorgTransaction{
+		{{Tx: signed}},
+		{{Msg: &ethereum.CallMsg{From: account0, To: &to, Value: big.NewInt(1)}}},
+	}
+	if err := anvl.Reorg(3, blocks); err != nil {
+		t.Fatalf("Reorg failed: %v", err)
+	}
+
+	var opts struct {
+		Depth        uint64               `json:"depth"`
+		TxBlockPairs [][2]json.RawMessage `json:"txBlockPairs"`
+	}
+	if err := json.Unmarshal(calls["anvil_reorg"][0], &opts); err != nil {
+		t.Fatalf("decoding anvil_reorg params failed: %v", err)
+	}
+	if opts.Depth != 3 || len(opts.TxBlockPairs) != 2 {
+		t.Fatalf("unexpected anvil_reorg params: %s", calls["anvil_reorg"][0])
+	}
+	if raw, _ := signed.MarshalBinary(); string(opts.TxBlockPairs[0][0]) != `"`+hexutil.Encode(raw)+`"` {
+		t.Fatalf("signed tx not sent as raw bytes: %s", opts.TxBlockPairs[0][0])
+	}
+	if string(opts.TxBlockPairs[1][1]) != "1" {
+		t.Fatalf("unsigned tx sent for block %s, expected 1", opts.TxBlockPairs[1][1])
+	}
+
+	if err := anvl.Reorg(1, blocks); err == nil {
+		t.Fatalf("Reorg accepted more blocks than its depth")
+	}
+
+	if err := anvl.Rollback(0); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if len(calls["anvil_rollback"]) != 0 {
+		t.Fatalf("Rollback(0) sent a depth: %s", calls["anvil_rollback"])
+	}
+}
+
+// TestAnvil_SimulateReorgHashes checks that SimulateReorg reports the block
+// hashes returned by the node.
+func TestAnvil_SimulateReorgHashes(t *testing.T) {
+	reorged := false
+	hashOf := func(n uint64, reorged bool) common.Hash {
+		if reorged {
+			n += 100
+		}
+		return common.BigToHash(new(big.Int).SetUint64(n))
+	}
+	anvl := stubAnvil(t, func(method string, params []json.RawMessage) any {
+		switch method {
+		case "eth_blockNumber":
+			return "0x3"
+		case "eth_getBlockByNumber":
+			var n hexutil.Uint64
+			json.Unmarshal(params[0], &n)
+			// Only the hash is returned, so decoding a full header would fail.
+			return map[string]any{"hash": hashOf(uint64(n), reorged)}
+		case "anvil_reorg":
+			reorged = true
+		}
+		return nil
+	})
+
+	result, err := anvl.SimulateReorg(2, nil)
+	if err != nil {
+		t.Fatalf("SimulateReorg failed: %v", err)
+	}
+	if result.Ancestor != 1 {
+		t.Fatalf("Ancestor is %d, expected 1", result.Ancestor)
+	}
+	for i, n := range []uint64{2, 3} {
+		if result.OldHashes[i] != hashOf(n, false) || result.NewHashes[i] != hashOf(n, true) {
+			t.Fatalf("unexpected hashes for block %d: %+v", n, result)
+		}
+	}
+
+	if _, err := anvl.SimulateReorg(4, nil); err == nil {
+		t.Fatalf("SimulateReorg accepted a depth beyond the head block")
+	}
+}
+
+// TestAnvil_SimulateReorg replaces the last blocks and checks the reported hashes.
+func TestAnvil_SimulateReorg(t *testing.T) {
+	anvl, err := NewWithConfig(NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anvl.Close()
+
+	if err := anvl.Mine(big.NewInt(3), nil); err != nil {
+		t.Fatalf("Mine failed: %v", err)
+	}
+
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	blocks := [][]ReorgTransaction{
+		{{Msg: &ethereum.CallMsg{From: account0, To: &to, Value: big.NewInt(1)}}},
+	}
+	result, err := anvl.SimulateReorg(2, blocks)
+	if err != nil {
+		t.Fatalf("SimulateReorg failed: %v", err)
+	}
+
+	if result.Ancestor != 1 || len(result.OldHashes) != 2 || len(result.NewHashes) != 2 {
+		t.Fatalf("unexpected reorg result: %+v", result)
+	}
+	for i := range result.OldHashes {
+		if result.OldHashes[i] == result.NewHashes[i] {
+			t.Fatalf("block %d was not replaced", result.Ancestor+1+uint64(i))
+		}
+	}
+
+	block, err := anvl.EthClient().BlockByHash(context.Background(), result.NewHashes[0])
+	if err != nil {
+		t.Fatalf("BlockByHash failed: %v", err)
+	}
+	if len(block.Transactions()) != 1 {
+		t.Fatalf("replacement block has %d txs, expected 1", len(block.Transactions()))
+	}
+}
@@ -0,0 +1,117 @@
+package anvil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// StartupReport is the node configuration Anvil writes with --config-out.
+type StartupReport struct {
+	AvailableAccounts []common.Address
+	PrivateKeys       []*ecdsa.PrivateKey
+	// Wallet is nil if Anvil did not derive the accounts from a mnemonic.
+	Wallet           *StartupWallet
+	BaseFee          *big.Int
+	GasLimit         uint64
+	GenesisTimestamp uint64
+	ChainID          uint64
+}
+
+// StartupWallet is the mnemonic the dev accounts in a StartupReport were derived from.
+type StartupWallet struct {
+	Mnemonic       string `json:"mnemonic"`
+	DerivationPath string `json:"derivation_path"`
+}
+
+// UnmarshalJSON decodes the --config-out JSON. Numeric fields are accepted as
+// JSON numbers, decimal strings and hex strings.
+func (r *StartupReport) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		AvailableAccounts []common.Address `json:"available_accounts"`
+		PrivateKeys       []string         `json:"private_keys"`
+		Wallet            *StartupWallet   `json:"wallet"`
+		BaseFee           quantity         `json:"base_fee"`
+		GasLimit          quantity         `json:"gas_limit"`
+		GenesisTimestamp  quantity         `json:"genesis_timestamp"`
+		ChainID           quantity         `json:"chain_id"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = StartupReport{
+		AvailableAccounts: raw.AvailableAccounts,
+		Wallet:            raw.Wallet,
+		BaseFee:           raw.BaseFee.Big(),
+		GasLimit:          raw.GasLimit.Uint64(),
+		GenesisTimestamp:  raw.GenesisTimestamp.Uint64(),
+		ChainID:           raw.ChainID.Uint64(),
+	}
+	for _, hex := range raw.PrivateKeys {
+		key, err := crypto.ToECDSA(common.FromHex(hex))
+		if err != nil {
+			return fmt.Errorf("invalid private key in startup report: %w", err)
+		}
+		r.PrivateKeys = append(r.PrivateKeys, key)
+	}
+	return nil
+}
+
+// accounts returns the dev accounts listed in the report.
+func (r *StartupReport) accounts() []DevAccount {
+	accounts := make([]DevAccount, len(r.PrivateKeys))
+	for i, key := range r.PrivateKeys {
+		accounts[i] = DevAccount{Address: crypto.PubkeyToAddress(key.PublicKey), PrivateKey: key}
+	}
+	return accounts
+}
+
+// readStartupReport reads the --config-out file at path.
+func readStartupReport(path string) (*StartupReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading startup report: %w", err)
+	}
+
+	var report StartupReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("error parsing startup report %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// waitStartupReport polls the --config-out file at path until it holds a
+// complete report. Anvil writes the file after its RPC server is bound, so
+// it can be missing or partially written when waitReady returns.
+func waitStartupReport(ctx context.Context, proc *process, path string) (*StartupReport, error) {
+	for {
+		report, err := readStartupReport(path)
+		if err == nil {
+			return report, nil
+		}
+
+		select {
+		case <-proc.done:
+		case <-ctx.Done():
+		case <-time.After(100 * time.Millisecond):
+			continue
+		}
+		return nil, err
+	}
+}
+
+// StartupReport returns the configuration Anvil reported at startup, or nil
+// unless the instance was started with SetStartupReport. When present, the
+// accounts it lists are also returned by Accounts, which makes dev accounts
+// available for random mnemonics too.
+func (a *Anvil) StartupReport() *StartupReport {
+	return a.report
+}
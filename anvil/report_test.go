@@ -0,0 +1,76 @@
+package anvil
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAnvil_StartupReport checks parsing of the --config-out file.
+func TestAnvil_StartupReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	body := `{
+		"available_accounts": ["0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"],
+		"private_keys": ["0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"],
+		"endpoint": "127.0.0.1:8545",
+		"wallet": {"derivation_path": "m/44'/60'/0'/0/", "mnemonic": "test test test test test test test test test test test junk"},
+		"base_fee": "1000000000",
+		"gas_limit": "30000000",
+		"genesis_timestamp": "1700000000",
+		"chain_id": 31337
+	}`
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := readStartupReport(path)
+	if err != nil {
+		t.Fatalf("readStartupReport failed: %v", err)
+	}
+	if report.ChainID != 31337 || report.GasLimit != 30_000_000 || report.GenesisTimestamp != 1_700_000_000 || report.BaseFee.Int64() != 1e9 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Wallet == nil || report.Wallet.Mnemonic != DefaultMnemonic {
+		t.Fatalf("unexpected wallet: %+v", report.Wallet)
+	}
+
+	accounts := report.accounts()
+	if len(accounts) != 1 || accounts[0].Address != report.AvailableAccounts[0] {
+		t.Fatalf("unexpected accounts: %+v", accounts)
+	}
+
+	if _, err := readStartupReport(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("readStartupReport succeeded for a missing file")
+	}
+}
+
+// TestAnvil_WaitStartupReport checks that a report written after startup is
+// polled until it is complete, and that polling stops with the context.
+func TestAnvil_WaitStartupReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	body := `{"available_accounts": [], "private_keys": [], "chain_id": 31337}`
+	if err := os.WriteFile(path, []byte(body[:20]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(250 * time.Millisecond)
+		os.WriteFile(path, []byte(body), 0o644)
+	}()
+
+	proc := &process{done: make(chan struct{})}
+	report, err := waitStartupReport(context.Background(), proc, path)
+	if err != nil {
+		t.Fatalf("waitStartupReport failed: %v", err)
+	}
+	if report.ChainID != 31337 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := waitStartupReport(ctx, proc, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatalf("waitStartupReport succeeded for a missing file")
+	}
+}
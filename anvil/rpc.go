@@ -1,36 +1,71 @@
 package anvil
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/go-resty/resty/v2"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // Send transactions impersonating an externally owned account or contract.
 func (a Anvil) ImpersonateAccount(address common.Address) error {
-	_, err := makeRequest[any](a.url, "anvil_impersonateAccount", []any{address})
+	return a.ImpersonateAccountContext(context.Background(), address)
+}
+
+// ImpersonateAccountContext is like ImpersonateAccount but honors ctx for cancellation and deadlines.
+func (a Anvil) ImpersonateAccountContext(ctx context.Context, address common.Address) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_impersonateAccount", []any{address})
 	return err
 }
 
 // Stops impersonating an account or contract if previously set with ImpersonateAccount
 func (a Anvil) StopImpersonatingAccount(address common.Address) error {
-	_, err := makeRequest[bool](a.url, "anvil_stopImpersonatingAccount", []any{address})
+	return a.StopImpersonatingAccountContext(context.Background(), address)
+}
+
+// StopImpersonatingAccountContext is like StopImpersonatingAccount but honors ctx for cancellation and deadlines.
+func (a Anvil) StopImpersonatingAccountContext(ctx context.Context, address common.Address) error {
+	_, err := makeRequest[bool](ctx, a.rpcClient, "anvil_stopImpersonatingAccount", []any{address})
 	return err
 }
 
 // AutoImpersonateAccount accepts true to enable auto impersonation of accounts, and false to disable it.
 // When enabled, any transaction's sender will be automatically impersonated (same effect as impersonateAccount).
 func (a Anvil) AutoImpersonateAccount(enabled bool) error {
-	_, err := makeRequest[any](a.url, "anvil_autoImpersonateAccount", []any{enabled})
+	return a.AutoImpersonateAccountContext(context.Background(), enabled)
+}
+
+// AutoImpersonateAccountContext is like AutoImpersonateAccount but honors ctx for cancellation and deadlines.
+func (a Anvil) AutoImpersonateAccountContext(ctx context.Context, enabled bool) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_autoImpersonateAccount", []any{enabled})
+	return err
+}
+
+// ImpersonateSignature makes Anvil accept signature as a valid signature from
+// address, so transactions signed with it are treated as sent by address.
+func (a Anvil) ImpersonateSignature(signature []byte, address common.Address) error {
+	return a.ImpersonateSignatureContext(context.Background(), signature, address)
+}
+
+// ImpersonateSignatureContext is like ImpersonateSignature but honors ctx for cancellation and deadlines.
+func (a Anvil) ImpersonateSignatureContext(ctx context.Context, signature []byte, address common.Address) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_impersonateSignature", []any{hexutil.Bytes(signature), address})
 	return err
 }
 
 // GetAutomine returns true if automatic mining is enabled, and false otherwise.
 func (a Anvil) GetAutomine() (bool, error) {
-	res, err := makeRequest[bool](a.url, "anvil_getAutomine", []any{})
+	return a.GetAutomineContext(context.Background())
+}
+
+// GetAutomineContext is like GetAutomine but honors ctx for cancellation and deadlines.
+func (a Anvil) GetAutomineContext(ctx context.Context) (bool, error) {
+	res, err := makeRequest[bool](ctx, a.rpcClient, "anvil_getAutomine", []any{})
 	if err != nil {
 		return false, err
 	}
@@ -39,8 +74,13 @@ func (a Anvil) GetAutomine() (bool, error) {
 
 // GetBlobByHash returns the blob for a given KZG commitment versioned hash.
 func (a Anvil) GetBlobByHash(hash common.Hash) (string, error) {
+	return a.GetBlobByHashContext(context.Background(), hash)
+}
+
+// GetBlobByHashContext is like GetBlobByHash but honors ctx for cancellation and deadlines.
+func (a Anvil) GetBlobByHashContext(ctx context.Context, hash common.Hash) (string, error) {
 	// Return as hex string for flexibility.
-	res, err := makeRequest[string](a.url, "anvil_getBlobByHash", []any{hash})
+	res, err := makeRequest[string](ctx, a.rpcClient, "anvil_getBlobByHash", []any{hash})
 	if err != nil {
 		return "", err
 	}
@@ -49,7 +89,12 @@ func (a Anvil) GetBlobByHash(hash common.Hash) (string, error) {
 
 // GetBlobsByTransactionHash returns the blobs for a given transaction hash.
 func (a Anvil) GetBlobsByTransactionHash(txHash common.Hash) ([]string, error) {
-	res, err := makeRequest[[]string](a.url, "anvil_getBlobsByTransactionHash", []any{txHash})
+	return a.GetBlobsByTransactionHashContext(context.Background(), txHash)
+}
+
+// GetBlobsByTransactionHashContext is like GetBlobsByTransactionHash but honors ctx for cancellation and deadlines.
+func (a Anvil) GetBlobsByTransactionHashContext(ctx context.Context, txHash common.Hash) ([]string, error) {
+	res, err := makeRequest[[]string](ctx, a.rpcClient, "anvil_getBlobsByTransactionHash", []any{txHash})
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +104,12 @@ func (a Anvil) GetBlobsByTransactionHash(txHash common.Hash) ([]string, error) {
 // GetBlobSidecarsByBlockId returns the blob sidecars for a given block id.
 // Block ID can be a block number, hash, or tag like "latest".
 func (a Anvil) GetBlobSidecarsByBlockId(blockId string) (json.RawMessage, error) {
-	res, err := makeRequest[json.RawMessage](a.url, "anvil_getBlobSidecarsByBlockId", []any{blockId})
+	return a.GetBlobSidecarsByBlockIdContext(context.Background(), blockId)
+}
+
+// GetBlobSidecarsByBlockIdContext is like GetBlobSidecarsByBlockId but honors ctx for cancellation and deadlines.
+func (a Anvil) GetBlobSidecarsByBlockIdContext(ctx context.Context, blockId string) (json.RawMessage, error) {
+	res, err := makeRequest[json.RawMessage](ctx, a.rpcClient, "anvil_getBlobSidecarsByBlockId", []any{blockId})
 	if err != nil {
 		return nil, err
 	}
@@ -68,11 +118,16 @@ func (a Anvil) GetBlobSidecarsByBlockId(blockId string) (json.RawMessage, error)
 
 // GetBlobsByBlockId returns blobs for a given block ID, optionally filtered by a list of versioned hashes.
 func (a Anvil) GetBlobsByBlockId(blockId string, versionedHashes []common.Hash) (json.RawMessage, error) {
+	return a.GetBlobsByBlockIdContext(context.Background(), blockId, versionedHashes)
+}
+
+// GetBlobsByBlockIdContext is like GetBlobsByBlockId but honors ctx for cancellation and deadlines.
+func (a Anvil) GetBlobsByBlockIdContext(ctx context.Context, blockId string, versionedHashes []common.Hash) (json.RawMessage, error) {
 	params := []any{blockId}
 	if len(versionedHashes) > 0 {
 		params = append(params, versionedHashes)
 	}
-	res, err := makeRequest[json.RawMessage](a.url, "anvil_getBlobsByBlockId", params)
+	res, err := makeRequest[json.RawMessage](ctx, a.rpcClient, "anvil_getBlobsByBlockId", params)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +137,11 @@ func (a Anvil) GetBlobsByBlockId(blockId string, versionedHashes []common.Hash)
 // Mine mines a series of blocks.
 // If blocks or interval are nil, the defaults (1 block, 1 second) are used by Anvil.
 func (a Anvil) Mine(blocks, interval *big.Int) error {
+	return a.MineContext(context.Background(), blocks, interval)
+}
+
+// MineContext is like Mine but honors ctx for cancellation and deadlines.
+func (a Anvil) MineContext(ctx context.Context, blocks, interval *big.Int) error {
 	var params []any
 	if blocks != nil {
 		params = append(params, toHexQuantityBig(blocks))
@@ -89,98 +149,208 @@ func (a Anvil) Mine(blocks, interval *big.Int) error {
 	if interval != nil {
 		params = append(params, toHexQuantityBig(interval))
 	}
-	_, err := makeRequest[any](a.url, "anvil_mine", params)
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_mine", params)
 	return err
 }
 
 // DropTransaction removes a transaction from the pool and may return the dropped hash.
 func (a Anvil) DropTransaction(txHash common.Hash) (*common.Hash, error) {
-	res, err := makeRequest[*common.Hash](a.url, "anvil_dropTransaction", []any{txHash})
+	return a.DropTransactionContext(context.Background(), txHash)
+}
+
+// DropTransactionContext is like DropTransaction but honors ctx for cancellation and deadlines.
+func (a Anvil) DropTransactionContext(ctx context.Context, txHash common.Hash) (*common.Hash, error) {
+	res, err := makeRequest[*common.Hash](ctx, a.rpcClient, "anvil_dropTransaction", []any{txHash})
 	if err != nil {
 		return nil, err
 	}
 	return *res, nil
 }
 
+// DropAllTransactions removes all transactions from the pool.
+func (a Anvil) DropAllTransactions() error {
+	return a.DropAllTransactionsContext(context.Background())
+}
+
+// DropAllTransactionsContext is like DropAllTransactions but honors ctx for cancellation and deadlines.
+func (a Anvil) DropAllTransactionsContext(ctx context.Context) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_dropAllTransactions", []any{})
+	return err
+}
+
+// RemovePoolTransactions removes all transactions sent by address from the pool.
+func (a Anvil) RemovePoolTransactions(address common.Address) error {
+	return a.RemovePoolTransactionsContext(context.Background(), address)
+}
+
+// RemovePoolTransactionsContext is like RemovePoolTransactions but honors ctx for cancellation and deadlines.
+func (a Anvil) RemovePoolTransactionsContext(ctx context.Context, address common.Address) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_removePoolTransactions", []any{address})
+	return err
+}
+
 // Reset resets the fork to a fresh forked state, and optionally updates the fork config.
 // Pass nil to disable forking entirely.
 func (a Anvil) Reset(forkConfig any) error {
+	return a.ResetContext(context.Background(), forkConfig)
+}
+
+// ResetContext is like Reset but honors ctx for cancellation and deadlines.
+func (a Anvil) ResetContext(ctx context.Context, forkConfig any) error {
 	var params []any
 	if forkConfig != nil {
 		params = append(params, forkConfig)
 	}
-	_, err := makeRequest[any](a.url, "anvil_reset", params)
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_reset", params)
 	return err
 }
 
 // SetRpcUrl sets the backend RPC URL used for forking.
 func (a Anvil) SetRpcUrl(url string) error {
-	_, err := makeRequest[any](a.url, "anvil_setRpcUrl", []any{url})
+	return a.SetRpcUrlContext(context.Background(), url)
+}
+
+// SetRpcUrlContext is like SetRpcUrl but honors ctx for cancellation and deadlines.
+func (a Anvil) SetRpcUrlContext(ctx context.Context, url string) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setRpcUrl", []any{url})
 	return err
 }
 
 // SetBalance modifies the balance of an account.
 func (a Anvil) SetBalance(address common.Address, balance *big.Int) error {
-	_, err := makeRequest[any](a.url, "anvil_setBalance", []any{address, toHexQuantityBig(balance)})
+	return a.SetBalanceContext(context.Background(), address, balance)
+}
+
+// SetBalanceContext is like SetBalance but honors ctx for cancellation and deadlines.
+func (a Anvil) SetBalanceContext(ctx context.Context, address common.Address, balance *big.Int) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setBalance", []any{address, toHexQuantityBig(balance)})
+	return err
+}
+
+// AddBalance adds amount to the balance of the given address.
+func (a Anvil) AddBalance(address common.Address, amount *big.Int) error {
+	return a.AddBalanceContext(context.Background(), address, amount)
+}
+
+// AddBalanceContext is like AddBalance but honors ctx for cancellation and deadlines.
+func (a Anvil) AddBalanceContext(ctx context.Context, address common.Address, amount *big.Int) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_addBalance", []any{address, toHexQuantityBig(amount)})
 	return err
 }
 
 // SetCode sets the code of a contract.
 func (a Anvil) SetCode(address common.Address, codeHex string) error {
+	return a.SetCodeContext(context.Background(), address, codeHex)
+}
+
+// SetCodeContext is like SetCode but honors ctx for cancellation and deadlines.
+func (a Anvil) SetCodeContext(ctx context.Context, address common.Address, codeHex string) error {
 	// codeHex should be 0x-prefixed bytecode.
-	_, err := makeRequest[any](a.url, "anvil_setCode", []any{address, codeHex})
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setCode", []any{address, codeHex})
 	return err
 }
 
 // SetNonce sets the nonce of an address.
 func (a Anvil) SetNonce(address common.Address, nonce uint64) error {
-	_, err := makeRequest[any](a.url, "anvil_setNonce", []any{address, toHexQuantityUint64(nonce)})
+	return a.SetNonceContext(context.Background(), address, nonce)
+}
+
+// SetNonceContext is like SetNonce but honors ctx for cancellation and deadlines.
+func (a Anvil) SetNonceContext(ctx context.Context, address common.Address, nonce uint64) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setNonce", []any{address, toHexQuantityUint64(nonce)})
 	return err
 }
 
 // SetStorageAt writes a single storage slot of the account's storage.
 func (a Anvil) SetStorageAt(address common.Address, slot common.Hash, value common.Hash) (bool, error) {
-	res, err := makeRequest[bool](a.url, "anvil_setStorageAt", []any{address, slot, value})
+	return a.SetStorageAtContext(context.Background(), address, slot, value)
+}
+
+// SetStorageAtContext is like SetStorageAt but honors ctx for cancellation and deadlines.
+func (a Anvil) SetStorageAtContext(ctx context.Context, address common.Address, slot common.Hash, value common.Hash) (bool, error) {
+	res, err := makeRequest[bool](ctx, a.rpcClient, "anvil_setStorageAt", []any{address, slot, value})
 	if err != nil {
 		return false, err
 	}
 	return *res, nil
 }
 
+// SetERC20Allowance sets the allowance of spender over owner's balance of the
+// ERC20 token, without requiring an approve transaction from owner.
+func (a Anvil) SetERC20Allowance(owner, spender, token common.Address, amount *big.Int) error {
+	return a.SetERC20AllowanceContext(context.Background(), owner, spender, token, amount)
+}
+
+// SetERC20AllowanceContext is like SetERC20Allowance but honors ctx for cancellation and deadlines.
+func (a Anvil) SetERC20AllowanceContext(ctx context.Context, owner, spender, token common.Address, amount *big.Int) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setERC20Allowance", []any{owner, spender, token, toHexQuantityBig(amount)})
+	return err
+}
+
 // SetCoinbase sets the coinbase (block author) address.
 func (a Anvil) SetCoinbase(address common.Address) error {
-	_, err := makeRequest[any](a.url, "anvil_setCoinbase", []any{address})
+	return a.SetCoinbaseContext(context.Background(), address)
+}
+
+// SetCoinbaseContext is like SetCoinbase but honors ctx for cancellation and deadlines.
+func (a Anvil) SetCoinbaseContext(ctx context.Context, address common.Address) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setCoinbase", []any{address})
 	return err
 }
 
 // SetLoggingEnabled enables or disables logging.
 func (a Anvil) SetLoggingEnabled(enabled bool) error {
-	_, err := makeRequest[any](a.url, "anvil_setLoggingEnabled", []any{enabled})
+	return a.SetLoggingEnabledContext(context.Background(), enabled)
+}
+
+// SetLoggingEnabledContext is like SetLoggingEnabled but honors ctx for cancellation and deadlines.
+func (a Anvil) SetLoggingEnabledContext(ctx context.Context, enabled bool) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setLoggingEnabled", []any{enabled})
 	return err
 }
 
 // SetMinGasPrice sets the minimum gas price for the node.
 func (a Anvil) SetMinGasPrice(price *big.Int) error {
-	_, err := makeRequest[any](a.url, "anvil_setMinGasPrice", []any{toHexQuantityBig(price)})
+	return a.SetMinGasPriceContext(context.Background(), price)
+}
+
+// SetMinGasPriceContext is like SetMinGasPrice but honors ctx for cancellation and deadlines.
+func (a Anvil) SetMinGasPriceContext(ctx context.Context, price *big.Int) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setMinGasPrice", []any{toHexQuantityBig(price)})
 	return err
 }
 
 // SetNextBlockBaseFeePerGas sets the base fee of the next block.
 func (a Anvil) SetNextBlockBaseFeePerGas(baseFee *big.Int) error {
-	_, err := makeRequest[any](a.url, "anvil_setNextBlockBaseFeePerGas", []any{toHexQuantityBig(baseFee)})
+	return a.SetNextBlockBaseFeePerGasContext(context.Background(), baseFee)
+}
+
+// SetNextBlockBaseFeePerGasContext is like SetNextBlockBaseFeePerGas but honors ctx for cancellation and deadlines.
+func (a Anvil) SetNextBlockBaseFeePerGasContext(ctx context.Context, baseFee *big.Int) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setNextBlockBaseFeePerGas", []any{toHexQuantityBig(baseFee)})
 	return err
 }
 
 // SetChainID sets the chain ID of the current EVM instance.
 func (a Anvil) SetChainID(chainID uint64) error {
-	_, err := makeRequest[any](a.url, "anvil_setChainId", []any{toHexQuantityUint64(chainID)})
+	return a.SetChainIDContext(context.Background(), chainID)
+}
+
+// SetChainIDContext is like SetChainID but honors ctx for cancellation and deadlines.
+func (a Anvil) SetChainIDContext(ctx context.Context, chainID uint64) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setChainId", []any{toHexQuantityUint64(chainID)})
 	return err
 }
 
 // DumpState returns a hex string representing the complete state of the chain.
 // It can be re-imported into a fresh instance of Anvil to restore the same state.
 func (a Anvil) DumpState() (string, error) {
-	res, err := makeRequest[string](a.url, "anvil_dumpState", []any{})
+	return a.DumpStateContext(context.Background())
+}
+
+// DumpStateContext is like DumpState but honors ctx for cancellation and deadlines.
+func (a Anvil) DumpStateContext(ctx context.Context) (string, error) {
+	res, err := makeRequest[string](ctx, a.rpcClient, "anvil_dumpState", []any{})
 	if err != nil {
 		return "", err
 	}
@@ -190,18 +360,14 @@ func (a Anvil) DumpState() (string, error) {
 // LoadState merges a state snapshot previously returned by DumpState into the current chain state.
 // Colliding accounts or storage slots will be overwritten.
 func (a Anvil) LoadState(stateHex string) (bool, error) {
-	res, err := makeRequest[bool](a.url, "anvil_loadState", []any{stateHex})
-	if err != nil {
-		return false, err
-	}
-	return *res, nil
+	return a.LoadStateContext(context.Background(), stateHex)
 }
 
-// NodeInfo retrieves the configuration parameters for the currently running Anvil node.
-func (a Anvil) NodeInfo() (map[string]any, error) {
-	res, err := makeRequest[map[string]any](a.url, "anvil_nodeInfo", []any{})
+// LoadStateContext is like LoadState but honors ctx for cancellation and deadlines.
+func (a Anvil) LoadStateContext(ctx context.Context, stateHex string) (bool, error) {
+	res, err := makeRequest[bool](ctx, a.rpcClient, "anvil_loadState", []any{stateHex})
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	return *res, nil
 }
@@ -210,19 +376,49 @@ func (a Anvil) NodeInfo() (map[string]any, error) {
 // If disabled, Anvil mines according to the configured interval; if enabled, blocks are mined
 // only when transactions arrive.
 func (a Anvil) EvmSetAutomine(enabled bool) error {
-	_, err := makeRequest[any](a.url, "evm_setAutomine", []any{enabled})
+	return a.EvmSetAutomineContext(context.Background(), enabled)
+}
+
+// EvmSetAutomineContext is like EvmSetAutomine but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmSetAutomineContext(ctx context.Context, enabled bool) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "evm_setAutomine", []any{enabled})
 	return err
 }
 
 // EvmSetIntervalMining sets the mining behavior to interval mode with the given interval in seconds.
 func (a Anvil) EvmSetIntervalMining(intervalSeconds uint64) error {
-	_, err := makeRequest[any](a.url, "evm_setIntervalMining", []any{toHexQuantityUint64(intervalSeconds)})
+	return a.EvmSetIntervalMiningContext(context.Background(), intervalSeconds)
+}
+
+// EvmSetIntervalMiningContext is like EvmSetIntervalMining but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmSetIntervalMiningContext(ctx context.Context, intervalSeconds uint64) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "evm_setIntervalMining", []any{toHexQuantityUint64(intervalSeconds)})
 	return err
 }
 
+// GetIntervalMining returns the interval mining period in seconds, or 0 if
+// interval mining is disabled.
+func (a Anvil) GetIntervalMining() (uint64, error) {
+	return a.GetIntervalMiningContext(context.Background())
+}
+
+// GetIntervalMiningContext is like GetIntervalMining but honors ctx for cancellation and deadlines.
+func (a Anvil) GetIntervalMiningContext(ctx context.Context) (uint64, error) {
+	res, err := makeRequest[*uint64](ctx, a.rpcClient, "anvil_getIntervalMining", []any{})
+	if err != nil || *res == nil {
+		return 0, err
+	}
+	return **res, nil
+}
+
 // EvmSnapshot snapshots the state of the blockchain at the current block and returns a snapshot id.
 func (a Anvil) EvmSnapshot() (string, error) {
-	res, err := makeRequest[string](a.url, "evm_snapshot", []any{})
+	return a.EvmSnapshotContext(context.Background())
+}
+
+// EvmSnapshotContext is like EvmSnapshot but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmSnapshotContext(ctx context.Context) (string, error) {
+	res, err := makeRequest[string](ctx, a.rpcClient, "evm_snapshot", []any{})
 	if err != nil {
 		return "", err
 	}
@@ -231,7 +427,12 @@ func (a Anvil) EvmSnapshot() (string, error) {
 
 // EvmRevert reverts the state of the blockchain to a previous snapshot id.
 func (a Anvil) EvmRevert(snapshotID string) (bool, error) {
-	res, err := makeRequest[bool](a.url, "evm_revert", []any{snapshotID})
+	return a.EvmRevertContext(context.Background(), snapshotID)
+}
+
+// EvmRevertContext is like EvmRevert but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmRevertContext(ctx context.Context, snapshotID string) (bool, error) {
+	res, err := makeRequest[bool](ctx, a.rpcClient, "evm_revert", []any{snapshotID})
 	if err != nil {
 		return false, err
 	}
@@ -240,7 +441,12 @@ func (a Anvil) EvmRevert(snapshotID string) (bool, error) {
 
 // EvmIncreaseTime jumps forward in time by the given number of seconds.
 func (a Anvil) EvmIncreaseTime(seconds int64) (int64, error) {
-	res, err := makeRequest[int64](a.url, "evm_increaseTime", []any{seconds})
+	return a.EvmIncreaseTimeContext(context.Background(), seconds)
+}
+
+// EvmIncreaseTimeContext is like EvmIncreaseTime but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmIncreaseTimeContext(ctx context.Context, seconds int64) (int64, error) {
+	res, err := makeRequest[int64](ctx, a.rpcClient, "evm_increaseTime", []any{seconds})
 	if err != nil {
 		return 0, err
 	}
@@ -249,240 +455,161 @@ func (a Anvil) EvmIncreaseTime(seconds int64) (int64, error) {
 
 // EvmSetNextBlockTimestamp sets the exact timestamp to use for the next block.
 func (a Anvil) EvmSetNextBlockTimestamp(timestamp uint64) error {
-	_, err := makeRequest[any](a.url, "evm_setNextBlockTimestamp", []any{toHexQuantityUint64(timestamp)})
-	return err
+	return a.EvmSetNextBlockTimestampContext(context.Background(), timestamp)
 }
 
-// SetBlockTimestampInterval sets a block timestamp interval; the next block timestamp is
-// computed as lastBlockTimestamp + interval.
-func (a Anvil) SetBlockTimestampInterval(intervalSeconds uint64) error {
-	_, err := makeRequest[any](a.url, "anvil_setBlockTimestampInterval", []any{toHexQuantityUint64(intervalSeconds)})
+// EvmSetNextBlockTimestampContext is like EvmSetNextBlockTimestamp but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmSetNextBlockTimestampContext(ctx context.Context, timestamp uint64) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "evm_setNextBlockTimestamp", []any{toHexQuantityUint64(timestamp)})
 	return err
 }
 
-// EvmSetBlockGasLimit sets the block gas limit for following blocks.
-func (a Anvil) EvmSetBlockGasLimit(limit *big.Int) error {
-	_, err := makeRequest[any](a.url, "evm_setBlockGasLimit", []any{toHexQuantityBig(limit)})
-	return err
+// SetTime sets the current time of the chain to timestamp. Subsequent blocks
+// are mined relative to it. It returns the number of seconds the clock moved forward.
+func (a Anvil) SetTime(timestamp uint64) (uint64, error) {
+	return a.SetTimeContext(context.Background(), timestamp)
 }
 
-// RemoveBlockTimestampInterval removes a previously set block timestamp interval, if it exists.
-func (a Anvil) RemoveBlockTimestampInterval() (bool, error) {
-	res, err := makeRequest[bool](a.url, "anvil_removeBlockTimestampInterval", []any{})
+// SetTimeContext is like SetTime but honors ctx for cancellation and deadlines.
+func (a Anvil) SetTimeContext(ctx context.Context, timestamp uint64) (uint64, error) {
+	res, err := makeRequest[uint64](ctx, a.rpcClient, "anvil_setTime", []any{toHexQuantityUint64(timestamp)})
 	if err != nil {
-		return false, err
+		return 0, err
 	}
 	return *res, nil
 }
 
-// EvmMine mines a single block. If a timestamp is provided, that timestamp is used for the block.
-func (a Anvil) EvmMine(timestamp ...uint64) error {
-	params := []any{}
-	if len(timestamp) > 0 {
-		params = append(params, toHexQuantityUint64(timestamp[0]))
-	}
-	_, err := makeRequest[any](a.url, "evm_mine", params)
-	return err
+// SetBlockTimestampInterval sets a block timestamp interval; the next block timestamp is
+// computed as lastBlockTimestamp + interval.
+func (a Anvil) SetBlockTimestampInterval(intervalSeconds uint64) error {
+	return a.SetBlockTimestampIntervalContext(context.Background(), intervalSeconds)
 }
 
-// EnableTraces turns on call traces for transactions returned to the user instead of just tx hash/receipt.
-func (a Anvil) EnableTraces() error {
-	_, err := makeRequest[any](a.url, "anvil_enableTraces", []any{})
+// SetBlockTimestampIntervalContext is like SetBlockTimestampInterval but honors ctx for cancellation and deadlines.
+func (a Anvil) SetBlockTimestampIntervalContext(ctx context.Context, intervalSeconds uint64) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_setBlockTimestampInterval", []any{toHexQuantityUint64(intervalSeconds)})
 	return err
 }
 
-// SendUnsignedTransaction executes a transaction regardless of signature status.
-// tx is a standard transaction object encoded as a map, similar to eth_sendTransaction.
-func (a Anvil) SendUnsignedTransaction(tx map[string]any) (common.Hash, error) {
-	res, err := makeRequest[common.Hash](a.url, "eth_sendUnsignedTransaction", []any{tx})
-	if err != nil {
-		return common.Hash{}, err
-	}
-	return *res, nil
+// EvmSetBlockGasLimit sets the block gas limit for following blocks.
+func (a Anvil) EvmSetBlockGasLimit(limit *big.Int) error {
+	return a.EvmSetBlockGasLimitContext(context.Background(), limit)
 }
 
-// TxpoolStatusResult is the standard result of txpool_status:
-// number of pending and queued txs, encoded as hex quantities.
-type TxpoolStatusResult struct {
-	Pending string `json:"pending"`
-	Queued  string `json:"queued"`
+// EvmSetBlockGasLimitContext is like EvmSetBlockGasLimit but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmSetBlockGasLimitContext(ctx context.Context, limit *big.Int) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "evm_setBlockGasLimit", []any{toHexQuantityBig(limit)})
+	return err
 }
 
-// TxpoolStatus returns the number of transactions currently pending and queued in the txpool.
-func (a Anvil) TxpoolStatus() (TxpoolStatusResult, error) {
-	res, err := makeRequest[TxpoolStatusResult](a.url, "txpool_status", []any{})
-	if err != nil {
-		return TxpoolStatusResult{}, err
-	}
-	return *res, nil
+// RemoveBlockTimestampInterval removes a previously set block timestamp interval, if it exists.
+func (a Anvil) RemoveBlockTimestampInterval() (bool, error) {
+	return a.RemoveBlockTimestampIntervalContext(context.Background())
 }
 
-// TxpoolInspect returns a human-readable summary of transactions currently pending and queued.
-// The exact shape is nested maps as in Geth; we expose it as generic JSON.
-func (a Anvil) TxpoolInspect() (map[string]any, error) {
-	res, err := makeRequest[map[string]any](a.url, "txpool_inspect", []any{})
+// RemoveBlockTimestampIntervalContext is like RemoveBlockTimestampInterval but honors ctx for cancellation and deadlines.
+func (a Anvil) RemoveBlockTimestampIntervalContext(ctx context.Context) (bool, error) {
+	res, err := makeRequest[bool](ctx, a.rpcClient, "anvil_removeBlockTimestampInterval", []any{})
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	return *res, nil
 }
 
-// TxpoolContent returns the details of all transactions currently pending and queued.
-func (a Anvil) TxpoolContent() (map[string]any, error) {
-	res, err := makeRequest[map[string]any](a.url, "txpool_content", []any{})
-	if err != nil {
-		return nil, err
-	}
-	return *res, nil
+// EvmMine mines a single block. If a timestamp is provided, that timestamp is used for the block.
+func (a Anvil) EvmMine(timestamp ...uint64) error {
+	return a.EvmMineContext(context.Background(), timestamp...)
 }
 
-// OTSGetApiLevel returns the Otterscan API level (simple version number).
-func (a Anvil) OTSGetApiLevel() (uint64, error) {
-	res, err := makeRequest[uint64](a.url, "ots_getApiLevel", []any{})
-	if err != nil {
-		return 0, err
+// EvmMineContext is like EvmMine but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmMineContext(ctx context.Context, timestamp ...uint64) error {
+	params := []any{}
+	if len(timestamp) > 0 {
+		params = append(params, toHexQuantityUint64(timestamp[0]))
 	}
-	return *res, nil
-}
-
-// OTSInternalOperation is a simplified representation of an internal operation from ots_getInternalOperations.
-type OTSInternalOperation struct {
-	Type uint8          `json:"type"`
-	From common.Address `json:"from"`
-	To   common.Address `json:"to"`
-	// Value is the amount of ETH transferred, as a hex quantity string.
-	Value string `json:"value"`
+	_, err := makeRequest[any](ctx, a.rpcClient, "evm_mine", params)
+	return err
 }
 
-// OTSGetInternalOperations returns internal ETH transfers for a transaction.
-func (a Anvil) OTSGetInternalOperations(txHash common.Hash) ([]OTSInternalOperation, error) {
-	res, err := makeRequest[[]OTSInternalOperation](a.url, "ots_getInternalOperations", []any{txHash.Hex()})
-	if err != nil {
-		return nil, err
-	}
-	return *res, nil
+// MinedBlock is a block returned by EvmMineDetailed, including its full transactions.
+type MinedBlock struct {
+	*types.Header
+	Transactions []*RPCTransaction
 }
 
-// OTSHasCode checks if an address contains deployed code at a specific block (or "latest").
-func (a Anvil) OTSHasCode(address common.Address, blockTag string) (bool, error) {
-	res, err := makeRequest[bool](a.url, "ots_hasCode", []any{address.Hex(), blockTag})
-	if err != nil {
-		return false, err
+// UnmarshalJSON decodes a block object with full transactions.
+func (b *MinedBlock) UnmarshalJSON(data []byte) error {
+	var body struct {
+		Transactions []*RPCTransaction `json:"transactions"`
 	}
-	return *res, nil
-}
-
-// OTSGetTransactionError returns the raw revert data for a transaction, or "0x" on success / no-reason failure.
-func (a Anvil) OTSGetTransactionError(txHash common.Hash) (string, error) {
-	res, err := makeRequest[string](a.url, "ots_getTransactionError", []any{txHash.Hex()})
-	if err != nil {
-		return "", err
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
 	}
-	return *res, nil
-}
 
-// OTSTraceTransaction returns a call tree trace for a transaction.
-func (a Anvil) OTSTraceTransaction(txHash common.Hash) (json.RawMessage, error) {
-	res, err := makeRequest[json.RawMessage](a.url, "ots_traceTransaction", []any{txHash.Hex()})
-	if err != nil {
-		return nil, err
+	b.Header = new(types.Header)
+	if err := b.Header.UnmarshalJSON(data); err != nil {
+		return err
 	}
-	return *res, nil
+	b.Transactions = body.Transactions
+	return nil
 }
 
-// OTSGetBlockDetails returns a tailored block object for a given block number.
-func (a Anvil) OTSGetBlockDetails(blockNumber uint64) (json.RawMessage, error) {
-	res, err := makeRequest[json.RawMessage](a.url, "ots_getBlockDetails", []any{toHexQuantityUint64(blockNumber)})
-	if err != nil {
-		return nil, err
-	}
-	return *res, nil
+// EvmMineDetailed is like EvmMine but returns the mined block with its transactions.
+func (a Anvil) EvmMineDetailed(timestamp ...uint64) ([]MinedBlock, error) {
+	return a.EvmMineDetailedContext(context.Background(), timestamp...)
 }
 
-// OTSGetBlockTransactions returns paginated transaction + receipt data for a given block.
-func (a Anvil) OTSGetBlockTransactions(blockNumber uint64, pageSize uint64) (json.RawMessage, error) {
-	params := []any{toHexQuantityUint64(blockNumber), pageSize}
-	res, err := makeRequest[json.RawMessage](a.url, "ots_getBlockTransactions", params)
-	if err != nil {
-		return nil, err
+// EvmMineDetailedContext is like EvmMineDetailed but honors ctx for cancellation and deadlines.
+func (a Anvil) EvmMineDetailedContext(ctx context.Context, timestamp ...uint64) ([]MinedBlock, error) {
+	params := []any{}
+	if len(timestamp) > 0 {
+		params = append(params, toHexQuantityUint64(timestamp[0]))
 	}
-	return *res, nil
-}
-
-// OTSSearchTransactionsBefore searches paginated inbound/outbound/internal transactions for an address before a block.
-func (a Anvil) OTSSearchTransactionsBefore(address common.Address, blockNumber uint64, pageSize uint64) (json.RawMessage, error) {
-	params := []any{address.Hex(), blockNumber, pageSize}
-	res, err := makeRequest[json.RawMessage](a.url, "ots_searchTransactionsBefore", params)
+	res, err := makeRequest[[]MinedBlock](ctx, a.rpcClient, "evm_mine_detailed", params)
 	if err != nil {
 		return nil, err
 	}
 	return *res, nil
 }
 
-// OTSSearchTransactionsAfter searches paginated inbound/outbound/internal transactions for an address after a block.
-func (a Anvil) OTSSearchTransactionsAfter(address common.Address, blockNumber uint64, pageSize uint64) (json.RawMessage, error) {
-	params := []any{address.Hex(), blockNumber, pageSize}
-	res, err := makeRequest[json.RawMessage](a.url, "ots_searchTransactionsAfter", params)
-	if err != nil {
-		return nil, err
-	}
-	return *res, nil
+// EnableTraces turns on call traces for transactions returned to the user instead of just tx hash/receipt.
+func (a Anvil) EnableTraces() error {
+	return a.EnableTracesContext(context.Background())
 }
 
-// OTSGetTransactionBySenderAndNonce returns the transaction hash for a given sender and nonce, or "" if not found.
-func (a Anvil) OTSGetTransactionBySenderAndNonce(sender common.Address, nonce uint64) (string, error) {
-	params := []any{sender.Hex(), nonce}
-	res, err := makeRequest[string](a.url, "ots_getTransactionBySenderAndNonce", params)
-	if err != nil {
-		return "", err
-	}
-	return *res, nil
+// EnableTracesContext is like EnableTraces but honors ctx for cancellation and deadlines.
+func (a Anvil) EnableTracesContext(ctx context.Context) error {
+	_, err := makeRequest[any](ctx, a.rpcClient, "anvil_enableTraces", []any{})
+	return err
 }
 
-// OTSContractCreator is the result of ots_getContractCreator.
-type OTSContractCreator struct {
-	Hash    common.Hash    `json:"hash"`
-	Creator common.Address `json:"creator"`
+// SendUnsignedTransaction executes a transaction regardless of signature status.
+// tx is a standard transaction object encoded as a map, similar to eth_sendTransaction.
+func (a Anvil) SendUnsignedTransaction(tx map[string]any) (common.Hash, error) {
+	return a.SendUnsignedTransactionContext(context.Background(), tx)
 }
 
-// OTSGetContractCreator returns the tx hash and creator address that deployed a contract,
-// or (nil, nil) if the address is not a contract.
-func (a Anvil) OTSGetContractCreator(address common.Address) (*OTSContractCreator, error) {
-	res, err := makeRequest[*OTSContractCreator](a.url, "ots_getContractCreator", []any{address.Hex()})
+// SendUnsignedTransactionContext is like SendUnsignedTransaction but honors ctx for cancellation and deadlines.
+func (a Anvil) SendUnsignedTransactionContext(ctx context.Context, tx map[string]any) (common.Hash, error) {
+	res, err := makeRequest[common.Hash](ctx, a.rpcClient, "eth_sendUnsignedTransaction", []any{tx})
 	if err != nil {
-		return nil, err
+		return common.Hash{}, err
 	}
 	return *res, nil
 }
 
-func makeRequest[T any](url string, method string, params []any) (*T, error) {
-	type Response struct {
-		Result T `json:"result"`
-	}
-
-	client := resty.New().SetJSONMarshaler(json.Marshal).SetJSONUnmarshaler(json.Unmarshal)
-
-	resp, err := client.R().
-		SetBody(map[string]any{
-			"method":  method,
-			"params":  params,
-			"id":      1,
-			"jsonrpc": "2.0",
-		}).
-		Post(url)
-	if err != nil {
-		return nil, err
+func makeRequest[T any](ctx context.Context, client *rpc.Client, method string, params []any) (*T, error) {
+	if params == nil {
+		params = []any{}
 	}
 
-	defer resp.RawResponse.Body.Close()
-
-	var response Response
-	err = json.Unmarshal(resp.Body(), &response)
+	var result T
+	err := client.CallContext(ctx, &result, method, params...)
 	if err != nil {
-		return nil, err
+		return nil, toRPCError(method, err)
 	}
 
-	return &response.Result, nil
+	return &result, nil
 }
 
 // toHexQuantity turns a uint64 into a 0x-prefixed hex quantity string.
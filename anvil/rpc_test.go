@@ -1,12 +1,19 @@
 package anvil
 
 import (
+	"context"
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// allowanceTokenCode is runtime bytecode that answers allowance(owner, spender)
+// from an OpenZeppelin-style mapping at slot 1, which is all
+// anvil_setERC20Allowance needs to locate the slot.
+const allowanceTokenCode = "0x6004356000526001602052604060002060205260243560005260406000205460005260206000f3"
+
 // TestAnvil_Impersonation shows basic account impersonation control.
 func TestAnvil_Impersonation(t *testing.T) {
 	anvl, err := NewWithConfig(NewConfig())
@@ -195,7 +202,17 @@ func TestAnvil_SnapshotsAndForking(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NodeInfo failed: %v", err)
 	}
-	t.Logf("NodeInfo chainId: %v", info["chainId"])
+	if info.Environment.ChainID != 31337 {
+		t.Fatalf("NodeInfo reported chain id %d, expected 31337", info.Environment.ChainID)
+	}
+
+	meta, err := anvl.Metadata()
+	if err != nil {
+		t.Fatalf("Metadata failed: %v", err)
+	}
+	if meta.ChainID != info.Environment.ChainID {
+		t.Fatalf("unexpected metadata: %+v", meta)
+	}
 }
 
 // TestAnvil_Blobs exercises blob-related helpers.
@@ -271,25 +288,21 @@ func TestAnvil_Txpool(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TxpoolStatus failed: %v", err)
 	}
-	t.Logf("pending: %s queued: %s", status.Pending, status.Queued)
+	t.Logf("pending: %d queued: %d", status.Pending, status.Queued)
 
 	inspect, err := anvl.TxpoolInspect()
 	if err != nil {
 		t.Fatalf("TxpoolInspect failed: %v", err)
 	}
-	if inspect == nil {
-		t.Fatalf("TxpoolInspect returned nil map")
-	}
-	t.Logf("inspect keys: %v", inspect)
+	t.Logf("inspect pending: %d queued: %d", len(inspect.Pending), len(inspect.Queued))
 
 	content, err := anvl.TxpoolContent()
 	if err != nil {
 		t.Fatalf("TxpoolContent failed: %v", err)
 	}
-	if content == nil {
-		t.Fatalf("TxpoolContent returned nil map")
+	if content.Pending.Len() != int(status.Pending) {
+		t.Fatalf("TxpoolContent returned %d pending txs, status reported %d", content.Pending.Len(), status.Pending)
 	}
-	t.Logf("content keys: %v", content)
 }
 
 // TestAnvil_Otterscan exercises a subset of the ots_* helpers.
@@ -317,3 +330,116 @@ func TestAnvil_Otterscan(t *testing.T) {
 	// so we only check that they don't panic / error when given dummy input.
 	// You can add more assertions when wiring to a real chain.
 }
+
+// TestAnvil_Cheats exercises the remaining anvil_ helpers.
+func TestAnvil_Cheats(t *testing.T) {
+	anvl, err := NewWithConfig(NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anvl.Close()
+
+	addr := account0
+	other := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	// Balance
+	before, err := anvl.EthClient().BalanceAt(context.Background(), addr, nil)
+	if err != nil {
+		t.Fatalf("BalanceAt failed: %v", err)
+	}
+	if err := anvl.AddBalance(addr, big.NewInt(1e18)); err != nil {
+		t.Fatalf("AddBalance failed: %v", err)
+	}
+	after, err := anvl.EthClient().BalanceAt(context.Background(), addr, nil)
+	if err != nil {
+		t.Fatalf("BalanceAt failed: %v", err)
+	}
+	if new(big.Int).Sub(after, before).Cmp(big.NewInt(1e18)) != 0 {
+		t.Fatalf("AddBalance changed balance from %s to %s", before, after)
+	}
+
+	// Time
+	if _, err := anvl.SetTime(2_000_000_000); err != nil {
+		t.Fatalf("SetTime failed: %v", err)
+	}
+	if err := anvl.EvmMine(); err != nil {
+		t.Fatalf("EvmMine failed: %v", err)
+	}
+	if header, err := anvl.EthClient().HeaderByNumber(context.Background(), nil); err != nil {
+		t.Fatalf("HeaderByNumber failed: %v", err)
+	} else if header.Time != 2_000_000_000 {
+		t.Fatalf("next block timestamp is %d, expected 2000000000", header.Time)
+	}
+
+	// Interval mining
+	if err := anvl.EvmSetIntervalMining(7); err != nil {
+		t.Fatalf("EvmSetIntervalMining failed: %v", err)
+	}
+	if interval, err := anvl.GetIntervalMining(); err != nil {
+		t.Fatalf("GetIntervalMining failed: %v", err)
+	} else if interval != 7 {
+		t.Fatalf("GetIntervalMining returned %d, expected 7", interval)
+	}
+	if err := anvl.EvmSetIntervalMining(0); err != nil {
+		t.Fatalf("EvmSetIntervalMining(0) failed: %v", err)
+	}
+
+	// Pool
+	if err := anvl.EvmSetAutomine(false); err != nil {
+		t.Fatalf("EvmSetAutomine(false) failed: %v", err)
+	}
+	tx := map[string]any{"from": addr.Hex(), "to": other.Hex(), "value": "0x1"}
+	if _, err := anvl.SendUnsignedTransaction(tx); err != nil {
+		t.Fatalf("SendUnsignedTransaction failed: %v", err)
+	}
+	if err := anvl.RemovePoolTransactions(addr); err != nil {
+		t.Fatalf("RemovePoolTransactions failed: %v", err)
+	}
+	if _, err := anvl.SendUnsignedTransaction(tx); err != nil {
+		t.Fatalf("SendUnsignedTransaction failed: %v", err)
+	}
+	if err := anvl.DropAllTransactions(); err != nil {
+		t.Fatalf("DropAllTransactions failed: %v", err)
+	}
+	if status, err := anvl.TxpoolStatus(); err != nil {
+		t.Fatalf("TxpoolStatus failed: %v", err)
+	} else if status.Pending != 0 {
+		t.Fatalf("pool still has %d pending txs", status.Pending)
+	}
+
+	// Detailed mining
+	if _, err := anvl.SendUnsignedTransaction(tx); err != nil {
+		t.Fatalf("SendUnsignedTransaction failed: %v", err)
+	}
+	blocks, err := anvl.EvmMineDetailed()
+	if err != nil {
+		t.Fatalf("EvmMineDetailed failed: %v", err)
+	}
+	if len(blocks) != 1 || len(blocks[0].Transactions) != 1 {
+		t.Fatalf("EvmMineDetailed returned unexpected blocks: %+v", blocks)
+	}
+
+	// Allowance
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	if err := anvl.SetCode(token, allowanceTokenCode); err != nil {
+		t.Fatalf("SetCode failed: %v", err)
+	}
+	if err := anvl.SetERC20Allowance(addr, other, token, big.NewInt(42)); err != nil {
+		t.Fatalf("SetERC20Allowance failed: %v", err)
+	}
+	input := append(common.FromHex("0xdd62ed3e"), common.LeftPadBytes(addr.Bytes(), 32)...)
+	input = append(input, common.LeftPadBytes(other.Bytes(), 32)...)
+	out, err := anvl.EthClient().CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: input}, nil)
+	if err != nil {
+		t.Fatalf("allowance call failed: %v", err)
+	}
+	if allowance := new(big.Int).SetBytes(out); allowance.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("allowance is %s, expected 42", allowance)
+	}
+
+	// Signatures
+	sig := make([]byte, 65)
+	if err := anvl.ImpersonateSignature(sig, other); err != nil {
+		t.Fatalf("ImpersonateSignature failed: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package anvil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WithSnapshot takes a snapshot, runs fn and reverts to the snapshot again,
+// whether fn returns an error, panics or calls runtime.Goexit (as t.Fatal
+// does). Calls may be nested; each level reverts only its own changes.
+//
+// The returned error joins the error of fn with any error reverting, which
+// includes Anvil reporting that the snapshot no longer exists.
+func (a Anvil) WithSnapshot(fn func() error) error {
+	return a.WithSnapshotContext(context.Background(), fn)
+}
+
+// WithSnapshotContext is like WithSnapshot but honors ctx for cancellation and deadlines.
+// The revert is sent even if ctx is done by the time fn returns.
+func (a Anvil) WithSnapshotContext(ctx context.Context, fn func() error) (err error) {
+	id, err := a.EvmSnapshotContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		err = errors.Join(err, a.revertSnapshot(context.WithoutCancel(ctx), id))
+	}()
+
+	return fn()
+}
+
+// revertSnapshot reverts to snapshot id and fails if Anvil did not revert.
+func (a Anvil) revertSnapshot(ctx context.Context, id string) error {
+	ok, err := a.EvmRevertContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error reverting to snapshot %s: %w", id, err)
+	}
+	if !ok {
+		return fmt.Errorf("snapshot %s could not be reverted", id)
+	}
+	return nil
+}
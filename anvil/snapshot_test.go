@@ -0,0 +1,78 @@
+package anvil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// snapshotStub serves evm_snapshot and evm_revert with Anvil's semantics:
+// reverting to a snapshot also discards every snapshot taken after it.
+type snapshotStub struct {
+	next    int
+	live    []string
+	reverts []string
+}
+
+func (s *snapshotStub) handle(method string, params []json.RawMessage) any {
+	switch method {
+	case "evm_snapshot":
+		s.next++
+		id := fmt.Sprintf("0x%x", s.next)
+		s.live = append(s.live, id)
+		return id
+	case "evm_revert":
+		var id string
+		json.Unmarshal(params[0], &id)
+		s.reverts = append(s.reverts, id)
+		i := slices.Index(s.live, id)
+		if i < 0 {
+			return false
+		}
+		s.live = s.live[:i]
+		return true
+	}
+	return nil
+}
+
+// TestAnvil_WithSnapshot checks that WithSnapshot reverts on errors and panics and when nested.
+func TestAnvil_WithSnapshot(t *testing.T) {
+	stub := &snapshotStub{}
+	anvl := stubAnvil(t, stub.handle)
+
+	errBody := errors.New("body failed")
+	err := anvl.WithSnapshot(func() error {
+		return anvl.WithSnapshot(func() error { return errBody })
+	})
+	if !errors.Is(err, errBody) {
+		t.Fatalf("WithSnapshot returned %v, expected the body error", err)
+	}
+	if !slices.Equal(stub.reverts, []string{"0x2", "0x1"}) || len(stub.live) != 0 {
+		t.Fatalf("unexpected reverts %v, live %v", stub.reverts, stub.live)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("panic was swallowed")
+			}
+		}()
+		anvl.WithSnapshot(func() error { panic("boom") })
+	}()
+	if len(stub.live) != 0 {
+		t.Fatalf("snapshot not reverted after panic, live %v", stub.live)
+	}
+
+	// Reverting the outer snapshot from inside discards the inner one.
+	err = anvl.WithSnapshot(func() error {
+		return anvl.WithSnapshot(func() error {
+			_, err := anvl.EvmRevert(stub.live[0])
+			return err
+		})
+	})
+	if err == nil {
+		t.Fatalf("WithSnapshot did not report the failed revert")
+	}
+}
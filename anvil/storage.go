@@ -0,0 +1,405 @@
+package anvil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Slot returns the storage slot with index n.
+func Slot(n uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(n))
+}
+
+// MappingSlot returns the slot of mapping[key] for a Solidity mapping stored
+// at slot: keccak256(key . slot). key is the 32 byte encoding of a value type
+// key, as returned by StorageWord; bytesN keys are left-aligned instead. Nested
+// mappings are addressed by calling MappingSlot on the result.
+func MappingSlot(slot, key common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+}
+
+// MappingSlotBytes is like MappingSlot for mappings with string or bytes keys,
+// which are hashed without padding.
+func MappingSlotBytes(slot common.Hash, key []byte) common.Hash {
+	return crypto.Keccak256Hash(key, slot.Bytes())
+}
+
+// VyperMappingSlot returns the slot of mapping[key] for a Vyper HashMap stored
+// at slot: keccak256(slot . key).
+func VyperMappingSlot(slot, key common.Hash) common.Hash {
+	return crypto.Keccak256Hash(slot.Bytes(), key.Bytes())
+}
+
+// StructSlot returns the slot of the struct member stored member slots after
+// the start of a struct at slot.
+func StructSlot(slot common.Hash, member uint64) common.Hash {
+	return addSlot(slot, new(big.Int).SetUint64(member))
+}
+
+// ArraySlot returns the location of array[index] for a dynamic array stored at
+// slot, whose elements are elementSize bytes long. Elements of 16 bytes or
+// less are packed, so the byte offset of the element within the slot is
+// returned as well.
+func ArraySlot(slot common.Hash, index, elementSize uint64) (common.Hash, int) {
+	return arrayElement(crypto.Keccak256Hash(slot.Bytes()), index, elementSize)
+}
+
+// FixedArraySlot is like ArraySlot for fixed-size arrays, whose elements are
+// stored in place starting at slot.
+func FixedArraySlot(slot common.Hash, index, elementSize uint64) (common.Hash, int) {
+	return arrayElement(slot, index, elementSize)
+}
+
+// arrayElement returns the slot and byte offset of element index of an array
+// whose data starts at base.
+func arrayElement(base common.Hash, index, elementSize uint64) (common.Hash, int) {
+	if elementSize <= 16 && elementSize > 0 {
+		perSlot := 32 / elementSize
+		return addSlot(base, new(big.Int).SetUint64(index/perSlot)), int(index % perSlot * elementSize)
+	}
+	slots := (elementSize + 31) / 32
+	return addSlot(base, new(big.Int).Mul(new(big.Int).SetUint64(index), new(big.Int).SetUint64(slots))), 0
+}
+
+// addSlot returns slot + n modulo 2^256.
+func addSlot(slot common.Hash, n *big.Int) common.Hash {
+	sum := new(big.Int).Add(slot.Big(), n)
+	return common.BytesToHash(sum.Bytes())
+}
+
+// PackValue returns word with the size bytes at byte offset (counted from the
+// least significant end, as in solc storage layouts) replaced by the lowest
+// size bytes of value.
+func PackValue(word common.Hash, offset, size int, value common.Hash) common.Hash {
+	end := common.HashLength - offset
+	copy(word[end-size:end], value[common.HashLength-size:])
+	return word
+}
+
+// UnpackValue returns the size bytes at byte offset of word, right-aligned.
+func UnpackValue(word common.Hash, offset, size int) common.Hash {
+	var value common.Hash
+	end := common.HashLength - offset
+	copy(value[common.HashLength-size:], word[end-size:end])
+	return value
+}
+
+// StorageWord encodes v as a 32 byte storage word. Supported types are
+// common.Hash, common.Address, *big.Int (negative values in two's complement),
+// the built-in integer types, bool and []byte of at most 32 bytes, which is
+// right-aligned.
+func StorageWord(v any) (common.Hash, error) {
+	switch v := v.(type) {
+	case common.Hash:
+		return v, nil
+	case common.Address:
+		return common.BytesToHash(v.Bytes()), nil
+	case *big.Int:
+		if v.BitLen() > 256 {
+			return common.Hash{}, fmt.Errorf("value %s does not fit in 256 bits", v)
+		}
+		return common.BytesToHash(new(big.Int).And(v, maxUint256).Bytes()), nil
+	case int:
+		return StorageWord(big.NewInt(int64(v)))
+	case int64:
+		return StorageWord(big.NewInt(v))
+	case uint:
+		return Slot(uint64(v)), nil
+	case uint64:
+		return Slot(v), nil
+	case bool:
+		if v {
+			return Slot(1), nil
+		}
+		return common.Hash{}, nil
+	case []byte:
+		if len(v) > common.HashLength {
+			return common.Hash{}, fmt.Errorf("%d byte value does not fit in a storage word", len(v))
+		}
+		return common.BytesToHash(v), nil
+	default:
+		return common.Hash{}, fmt.Errorf("unsupported storage value type %T", v)
+	}
+}
+
+// maxUint256 is 2^256 - 1.
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// StorageLayout is the storage layout of a contract as emitted by solc with
+// the storageLayout output selection or by `forge inspect <contract> storageLayout`.
+type StorageLayout struct {
+	Storage []StorageEntry         `json:"storage"`
+	Types   map[string]StorageType `json:"types"`
+}
+
+// StorageEntry is a state variable or struct member in a StorageLayout.
+type StorageEntry struct {
+	Label  string `json:"label"`
+	Offset int    `json:"offset"`
+	Slot   string `json:"slot"`
+	Type   string `json:"type"`
+}
+
+// StorageType describes a type referenced by a StorageLayout.
+type StorageType struct {
+	// Encoding is one of "inplace", "mapping", "dynamic_array" or "bytes".
+	Encoding      string         `json:"encoding"`
+	Label         string         `json:"label"`
+	NumberOfBytes string         `json:"numberOfBytes"`
+	Key           string         `json:"key,omitempty"`
+	Value         string         `json:"value,omitempty"`
+	Base          string         `json:"base,omitempty"`
+	Members       []StorageEntry `json:"members,omitempty"`
+}
+
+// StorageLocation is where a value lives in contract storage.
+type StorageLocation struct {
+	Slot common.Hash
+	// Offset is the byte offset of the value within Slot, counted from the
+	// least significant end.
+	Offset int
+	// Size is the size of the value in bytes.
+	Size int
+	// Type is the StorageLayout type id of the value.
+	Type string
+}
+
+// ParseStorageLayout parses a solc storage layout. Forge artifacts, which
+// carry the layout in a "storageLayout" field, are accepted too.
+func ParseStorageLayout(data []byte) (*StorageLayout, error) {
+	var artifact struct {
+		StorageLayout *StorageLayout `json:"storageLayout"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("error parsing storage layout: %w", err)
+	}
+	if artifact.StorageLayout != nil {
+		return artifact.StorageLayout, nil
+	}
+
+	var layout StorageLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("error parsing storage layout: %w", err)
+	}
+	return &layout, nil
+}
+
+// Locate returns the storage location of the variable at path. A path starts
+// with a state variable name followed by any number of struct members
+// (".amount"), mapping keys ("[0xabc...]", `["name"]`) and array indexes ("[3]"),
+// e.g. "balances[0xf39F...].amount".
+func (l *StorageLayout) Locate(path string) (StorageLocation, error) {
+	name, rest := splitPathIdent(path)
+	entry, ok := findEntry(l.Storage, name)
+	if !ok {
+		return StorageLocation{}, fmt.Errorf("no state variable %q in storage layout", name)
+	}
+
+	loc, err := l.entryLocation(common.Hash{}, entry)
+	if err != nil {
+		return StorageLocation{}, err
+	}
+
+	for rest != "" {
+		typ := l.Types[loc.Type]
+		switch rest[0] {
+		case '.':
+			name, rest = splitPathIdent(rest[1:])
+			if typ.Members == nil {
+				return StorageLocation{}, fmt.Errorf("%s is not a struct, cannot access .%s", typ.Label, name)
+			}
+			member, ok := findEntry(typ.Members, name)
+			if !ok {
+				return StorageLocation{}, fmt.Errorf("%s has no member %q", typ.Label, name)
+			}
+			if loc, err = l.entryLocation(loc.Slot, member); err != nil {
+				return StorageLocation{}, err
+			}
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return StorageLocation{}, fmt.Errorf("unterminated [ in %q", path)
+			}
+			key := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if loc, err = l.indexLocation(loc, typ, key); err != nil {
+				return StorageLocation{}, err
+			}
+
+		default:
+			return StorageLocation{}, fmt.Errorf("unexpected %q in %q", rest, path)
+		}
+	}
+
+	return loc, nil
+}
+
+// entryLocation returns the location of entry inside a struct (or the contract)
+// starting at base.
+func (l *StorageLayout) entryLocation(base common.Hash, entry StorageEntry) (StorageLocation, error) {
+	slot, ok := new(big.Int).SetString(entry.Slot, 10)
+	if !ok {
+		return StorageLocation{}, fmt.Errorf("invalid slot %q for %s", entry.Slot, entry.Label)
+	}
+	size, err := l.typeSize(entry.Type)
+	if err != nil {
+		return StorageLocation{}, err
+	}
+	return StorageLocation{Slot: addSlot(base, slot), Offset: entry.Offset, Size: size, Type: entry.Type}, nil
+}
+
+// indexLocation returns the location of the mapping value or array element key of loc.
+func (l *StorageLayout) indexLocation(loc StorageLocation, typ StorageType, key string) (StorageLocation, error) {
+	switch {
+	case typ.Encoding == "mapping":
+		slot, err := l.mappingSlot(loc.Slot, typ.Key, key)
+		if err != nil {
+			return StorageLocation{}, err
+		}
+		size, err := l.typeSize(typ.Value)
+		if err != nil {
+			return StorageLocation{}, err
+		}
+		return StorageLocation{Slot: slot, Size: size, Type: typ.Value}, nil
+
+	case typ.Base != "":
+		index, err := strconv.ParseUint(key, 0, 64)
+		if err != nil {
+			return StorageLocation{}, fmt.Errorf("invalid index %q into %s", key, typ.Label)
+		}
+		size, err := l.typeSize(typ.Base)
+		if err != nil {
+			return StorageLocation{}, err
+		}
+		slot, offset := FixedArraySlot(loc.Slot, index, uint64(size))
+		if typ.Encoding == "dynamic_array" {
+			slot, offset = ArraySlot(loc.Slot, index, uint64(size))
+		}
+		return StorageLocation{Slot: slot, Offset: offset, Size: size, Type: typ.Base}, nil
+
+	default:
+		return StorageLocation{}, fmt.Errorf("%s cannot be indexed", typ.Label)
+	}
+}
+
+// mappingSlot parses key according to the mapping key type and returns the
+// slot of its value.
+func (l *StorageLayout) mappingSlot(slot common.Hash, keyType, key string) (common.Hash, error) {
+	label := l.Types[keyType].Label
+	switch {
+	case label == "string":
+		return MappingSlotBytes(slot, []byte(strings.Trim(key, `"`))), nil
+
+	case label == "bytes":
+		return MappingSlotBytes(slot, common.FromHex(key)), nil
+
+	case strings.HasPrefix(label, "bytes"):
+		// Fixed-size byte arrays are left-aligned.
+		b := common.FromHex(key)
+		if len(b) > common.HashLength {
+			return common.Hash{}, fmt.Errorf("invalid %s key %q", label, key)
+		}
+		return MappingSlot(slot, common.BytesToHash(common.RightPadBytes(b, common.HashLength))), nil
+
+	case label == "address" || strings.HasPrefix(label, "contract ") || strings.HasPrefix(label, "address "):
+		if !common.IsHexAddress(key) {
+			return common.Hash{}, fmt.Errorf("invalid address key %q", key)
+		}
+		return MappingSlot(slot, common.BytesToHash(common.HexToAddress(key).Bytes())), nil
+
+	case label == "bool":
+		b, err := strconv.ParseBool(key)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("invalid bool key %q", key)
+		}
+		word, _ := StorageWord(b)
+		return MappingSlot(slot, word), nil
+
+	default:
+		// Integers and enums.
+		n, ok := new(big.Int).SetString(key, 0)
+		if !ok {
+			return common.Hash{}, fmt.Errorf("invalid %s key %q", label, key)
+		}
+		word, err := StorageWord(n)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return MappingSlot(slot, word), nil
+	}
+}
+
+// typeSize returns the number of bytes a value of type id occupies.
+func (l *StorageLayout) typeSize(id string) (int, error) {
+	typ, ok := l.Types[id]
+	if !ok {
+		return 0, fmt.Errorf("unknown type %q in storage layout", id)
+	}
+	size, err := strconv.Atoi(typ.NumberOfBytes)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q for %s", typ.NumberOfBytes, typ.Label)
+	}
+	return size, nil
+}
+
+// splitPathIdent splits the leading identifier off path.
+func splitPathIdent(path string) (string, string) {
+	end := strings.IndexAny(path, ".[")
+	if end < 0 {
+		return path, ""
+	}
+	return path[:end], path[end:]
+}
+
+// findEntry returns the entry labelled name.
+func findEntry(entries []StorageEntry, name string) (StorageEntry, bool) {
+	for _, entry := range entries {
+		if entry.Label == name {
+			return entry, true
+		}
+	}
+	return StorageEntry{}, false
+}
+
+// SetStorageVar sets the state variable at path of the contract at address
+// to value, using layout to find its slot. value is encoded with StorageWord.
+// Variables packed into a slot with others are updated without touching their
+// neighbours. See StorageLayout.Locate for the path syntax.
+func (a Anvil) SetStorageVar(address common.Address, layout *StorageLayout, path string, value any) error {
+	return a.SetStorageVarContext(context.Background(), address, layout, path, value)
+}
+
+// SetStorageVarContext is like SetStorageVar but honors ctx for cancellation and deadlines.
+func (a Anvil) SetStorageVarContext(ctx context.Context, address common.Address, layout *StorageLayout, path string, value any) error {
+	loc, err := layout.Locate(path)
+	if err != nil {
+		return err
+	}
+	if typ := layout.Types[loc.Type]; typ.Encoding != "inplace" || loc.Size > common.HashLength || typ.Members != nil || typ.Base != "" {
+		return fmt.Errorf("%s is a %s, only value types can be set", path, typ.Label)
+	}
+
+	word, err := StorageWord(value)
+	if err != nil {
+		return err
+	}
+
+	if loc.Size < common.HashLength {
+		current, err := makeRequest[common.Hash](ctx, a.rpcClient, "eth_getStorageAt", []any{address, loc.Slot, "latest"})
+		if err != nil {
+			return err
+		}
+		word = PackValue(*current, loc.Offset, loc.Size, word)
+	}
+
+	_, err = a.SetStorageAtContext(ctx, address, loc.Slot, word)
+	return err
+}
@@ -0,0 +1,142 @@
+package anvil
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testStorageLayout is the solc storage layout of
+//
+//	contract Vault {
+//	    struct Account { uint128 amount; uint64 nonce; bool frozen; }
+//	    uint128 small;
+//	    uint64 tiny;
+//	    mapping(address => Account) balances;
+//	    uint64[] packed;
+//	    mapping(string => mapping(uint256 => bool)) flags;
+//	}
+const testStorageLayout = `{
+	"storageLayout": {
+		"storage": [
+			{"label": "small", "offset": 0, "slot": "0", "type": "t_uint128"},
+			{"label": "tiny", "offset": 16, "slot": "0", "type": "t_uint64"},
+			{"label": "balances", "offset": 0, "slot": "1", "type": "t_mapping(t_address,t_struct(Account)6_storage)"},
+			{"label": "packed", "offset": 0, "slot": "2", "type": "t_array(t_uint64)dyn_storage"},
+			{"label": "flags", "offset": 0, "slot": "3", "type": "t_mapping(t_string_memory_ptr,t_mapping(t_uint256,t_bool))"}
+		],
+		"types": {
+			"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+			"t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+			"t_string_memory_ptr": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+			"t_uint64": {"encoding": "inplace", "label": "uint64", "numberOfBytes": "8"},
+			"t_uint128": {"encoding": "inplace", "label": "uint128", "numberOfBytes": "16"},
+			"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+			"t_array(t_uint64)dyn_storage": {"encoding": "dynamic_array", "label": "uint64[]", "numberOfBytes": "32", "base": "t_uint64"},
+			"t_mapping(t_address,t_struct(Account)6_storage)": {"encoding": "mapping", "label": "mapping(address => struct Vault.Account)", "numberOfBytes": "32", "key": "t_address", "value": "t_struct(Account)6_storage"},
+			"t_mapping(t_string_memory_ptr,t_mapping(t_uint256,t_bool))": {"encoding": "mapping", "label": "mapping(string => mapping(uint256 => bool))", "numberOfBytes": "32", "key": "t_string_memory_ptr", "value": "t_mapping(t_uint256,t_bool)"},
+			"t_mapping(t_uint256,t_bool)": {"encoding": "mapping", "label": "mapping(uint256 => bool)", "numberOfBytes": "32", "key": "t_uint256", "value": "t_bool"},
+			"t_struct(Account)6_storage": {"encoding": "inplace", "label": "struct Vault.Account", "numberOfBytes": "64", "members": [
+				{"label": "amount", "offset": 0, "slot": "0", "type": "t_uint128"},
+				{"label": "nonce", "offset": 16, "slot": "0", "type": "t_uint64"},
+				{"label": "frozen", "offset": 0, "slot": "1", "type": "t_bool"}
+			]}
+		}
+	}
+}`
+
+// TestStorageLayout_Locate checks slot computation for every supported path element.
+func TestStorageLayout_Locate(t *testing.T) {
+	layout, err := ParseStorageLayout([]byte(testStorageLayout))
+	if err != nil {
+		t.Fatalf("ParseStorageLayout failed: %v", err)
+	}
+
+	holder := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	account := crypto.Keccak256Hash(common.LeftPadBytes(holder.Bytes(), 32), Slot(1).Bytes())
+	packedData := crypto.Keccak256Hash(Slot(2).Bytes())
+	flags := crypto.Keccak256Hash(Slot(7).Bytes(), crypto.Keccak256Hash([]byte("admin"), Slot(3).Bytes()).Bytes())
+
+	tests := []struct {
+		path   string
+		slot   common.Hash
+		offset int
+		size   int
+	}{
+		{"small", Slot(0), 0, 16},
+		{"tiny", Slot(0), 16, 8},
+		{"balances[" + holder.Hex() + "].amount", account, 0, 16},
+		{"balances[" + holder.Hex() + "].nonce", account, 16, 8},
+		{"balances[" + holder.Hex() + "].frozen", addSlot(account, big.NewInt(1)), 0, 1},
+		{"packed[0]", packedData, 0, 8},
+		{"packed[5]", addSlot(packedData, big.NewInt(1)), 8, 8},
+		{`flags["admin"][7]`, flags, 0, 1},
+	}
+	for _, tt := range tests {
+		loc, err := layout.Locate(tt.path)
+		if err != nil {
+			t.Fatalf("Locate(%s) failed: %v", tt.path, err)
+		}
+		if loc.Slot != tt.slot || loc.Offset != tt.offset || loc.Size != tt.size {
+			t.Fatalf("Locate(%s) = %+v, expected slot %s offset %d size %d", tt.path, loc, tt.slot, tt.offset, tt.size)
+		}
+	}
+
+	for _, path := range []string{"missing", "small.x", "balances[0x12]", "packed[x]", "balances[" + holder.Hex() + "].other"} {
+		if _, err := layout.Locate(path); err == nil {
+			t.Fatalf("Locate(%s) succeeded", path)
+		}
+	}
+}
+
+// TestStorageLayout_PackValue checks that packed values leave their neighbours alone.
+func TestStorageLayout_PackValue(t *testing.T) {
+	word := common.HexToHash("0x1111111111111111111111111111111122222222222222222222222222222222")
+	packed := PackValue(word, 16, 8, Slot(0xabcd))
+
+	if want := common.HexToHash("0x1111111111111111000000000000abcd22222222222222222222222222222222"); packed != want {
+		t.Fatalf("PackValue = %s, expected %s", packed, want)
+	}
+	if got := UnpackValue(packed, 16, 8); got != Slot(0xabcd) {
+		t.Fatalf("UnpackValue = %s", got)
+	}
+}
+
+// TestAnvil_SetStorageVar checks that SetStorageVar merges packed values into the current slot.
+func TestAnvil_SetStorageVar(t *testing.T) {
+	layout, err := ParseStorageLayout([]byte(testStorageLayout))
+	if err != nil {
+		t.Fatalf("ParseStorageLayout failed: %v", err)
+	}
+
+	storage := map[common.Hash]common.Hash{Slot(0): common.HexToHash("0x05")}
+	anvl := stubAnvil(t, func(method string, params []json.RawMessage) any {
+		var slot common.Hash
+		json.Unmarshal(params[1], &slot)
+		switch method {
+		case "eth_getStorageAt":
+			return storage[slot]
+		case "anvil_setStorageAt":
+			var value common.Hash
+			json.Unmarshal(params[2], &value)
+			storage[slot] = value
+			return true
+		}
+		t.Errorf("unexpected method %s", method)
+		return nil
+	})
+
+	if err := anvl.SetStorageVar(account0, layout, "tiny", uint64(9)); err != nil {
+		t.Fatalf("SetStorageVar failed: %v", err)
+	}
+	if got := storage[Slot(0)]; got != common.HexToHash("0x0000000000000000000000000000000900000000000000000000000000000005") {
+		t.Fatalf("slot 0 is %s", got)
+	}
+
+	if err := anvl.SetStorageVar(account0, layout, "balances", uint64(1)); err == nil {
+		t.Fatalf("SetStorageVar wrote a mapping")
+	}
+}
@@ -0,0 +1,56 @@
+package anvil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// stubHandler answers one JSON-RPC request to a stub server. Returning an
+// *RPCError sends it as the JSON-RPC error object instead of a result.
+type stubHandler func(method string, params []json.RawMessage) any
+
+// newStubServer starts a JSON-RPC server that answers every request with fn.
+// The server is closed when the test finishes.
+func newStubServer(t *testing.T, fn stubHandler) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		res := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		result := fn(req.Method, req.Params)
+		if rpcErr, ok := result.(*RPCError); ok && rpcErr != nil {
+			res["error"] = rpcErr
+		} else {
+			res["result"] = result
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// stubAnvil returns an Anvil handle backed by a stub server answering with fn.
+func stubAnvil(t *testing.T, fn stubHandler) Anvil {
+	return dialTestAnvil(t, newStubServer(t, fn).URL)
+}
+
+// dialTestAnvil returns an Anvil handle talking to the JSON-RPC server at url.
+func dialTestAnvil(t *testing.T, url string) Anvil {
+	rpcClient, err := rpc.Dial(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(rpcClient.Close)
+	return Anvil{url: url, rpcClient: rpcClient, ethClient: ethclient.NewClient(rpcClient)}
+}
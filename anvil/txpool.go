@@ -0,0 +1,169 @@
+package anvil
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RPCTransaction is a transaction as returned by the JSON-RPC API. Besides the
+// transaction itself it carries the sender and, once mined, its position in the chain.
+type RPCTransaction struct {
+	*types.Transaction
+
+	From             common.Address
+	BlockHash        *common.Hash
+	BlockNumber      *big.Int
+	TransactionIndex *uint64
+}
+
+// UnmarshalJSON decodes an RPC transaction object.
+func (tx *RPCTransaction) UnmarshalJSON(data []byte) error {
+	var extra struct {
+		From             common.Address  `json:"from"`
+		BlockHash        *common.Hash    `json:"blockHash"`
+		BlockNumber      *hexutil.Big    `json:"blockNumber"`
+		TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+	}
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return err
+	}
+
+	tx.Transaction = new(types.Transaction)
+	if err := tx.Transaction.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	tx.From = extra.From
+	tx.BlockHash = extra.BlockHash
+	tx.BlockNumber = (*big.Int)(extra.BlockNumber)
+	tx.TransactionIndex = (*uint64)(extra.TransactionIndex)
+	return nil
+}
+
+// TxpoolTransactions holds txpool transactions keyed by sender and nonce.
+type TxpoolTransactions map[common.Address]map[uint64]*RPCTransaction
+
+// BySender returns the transactions of sender ordered by nonce.
+func (t TxpoolTransactions) BySender(sender common.Address) []*RPCTransaction {
+	txs := make([]*RPCTransaction, 0, len(t[sender]))
+	for _, nonce := range slices.Sorted(maps.Keys(t[sender])) {
+		txs = append(txs, t[sender][nonce])
+	}
+	return txs
+}
+
+// Len returns the total number of transactions across all senders.
+func (t TxpoolTransactions) Len() int {
+	n := 0
+	for _, byNonce := range t {
+		n += len(byNonce)
+	}
+	return n
+}
+
+// TxpoolContentResult is the result of txpool_content.
+type TxpoolContentResult struct {
+	Pending TxpoolTransactions `json:"pending"`
+	Queued  TxpoolTransactions `json:"queued"`
+}
+
+// TxpoolSummary is the human-readable summary of a transaction returned by
+// txpool_inspect, e.g. "0x...: 1 wei + 21000 gas × 1000000000 wei".
+type TxpoolSummary struct {
+	// To is the recipient, or nil for contract creations.
+	To       *common.Address
+	Value    *big.Int
+	Gas      uint64
+	GasPrice *big.Int
+	// Raw is the summary as returned by the node. It is always set, even if
+	// the remaining fields could not be parsed.
+	Raw string
+}
+
+// txpoolSummaryRe matches a txpool_inspect summary in the format used by Geth and Anvil.
+var txpoolSummaryRe = regexp.MustCompile(`^(0x[0-9a-fA-F]{40}|contract creation): (\d+) wei \+ (\d+) gas × (\d+) wei$`)
+
+// UnmarshalJSON decodes a summary string, parsing its fields when possible.
+func (s *TxpoolSummary) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.Raw); err != nil {
+		return err
+	}
+
+	m := txpoolSummaryRe.FindStringSubmatch(s.Raw)
+	if m == nil {
+		return nil
+	}
+
+	if m[1] != "contract creation" {
+		to := common.HexToAddress(m[1])
+		s.To = &to
+	}
+	s.Value, _ = new(big.Int).SetString(m[2], 10)
+	s.Gas, _ = strconv.ParseUint(m[3], 10, 64)
+	s.GasPrice, _ = new(big.Int).SetString(m[4], 10)
+	return nil
+}
+
+// TxpoolInspectResult is the result of txpool_inspect, keyed by sender and nonce.
+type TxpoolInspectResult struct {
+	Pending map[common.Address]map[uint64]TxpoolSummary `json:"pending"`
+	Queued  map[common.Address]map[uint64]TxpoolSummary `json:"queued"`
+}
+
+// TxpoolStatusResult is the standard result of txpool_status:
+// number of pending and queued txs.
+type TxpoolStatusResult struct {
+	Pending hexutil.Uint64 `json:"pending"`
+	Queued  hexutil.Uint64 `json:"queued"`
+}
+
+// TxpoolStatus returns the number of transactions currently pending and queued in the txpool.
+func (a Anvil) TxpoolStatus() (TxpoolStatusResult, error) {
+	return a.TxpoolStatusContext(context.Background())
+}
+
+// TxpoolStatusContext is like TxpoolStatus but honors ctx for cancellation and deadlines.
+func (a Anvil) TxpoolStatusContext(ctx context.Context) (TxpoolStatusResult, error) {
+	res, err := makeRequest[TxpoolStatusResult](ctx, a.rpcClient, "txpool_status", []any{})
+	if err != nil {
+		return TxpoolStatusResult{}, err
+	}
+	return *res, nil
+}
+
+// TxpoolInspect returns a human-readable summary of transactions currently pending and queued.
+func (a Anvil) TxpoolInspect() (TxpoolInspectResult, error) {
+	return a.TxpoolInspectContext(context.Background())
+}
+
+// TxpoolInspectContext is like TxpoolInspect but honors ctx for cancellation and deadlines.
+func (a Anvil) TxpoolInspectContext(ctx context.Context) (TxpoolInspectResult, error) {
+	res, err := makeRequest[TxpoolInspectResult](ctx, a.rpcClient, "txpool_inspect", []any{})
+	if err != nil {
+		return TxpoolInspectResult{}, err
+	}
+	return *res, nil
+}
+
+// TxpoolContent returns the details of all transactions currently pending and queued.
+func (a Anvil) TxpoolContent() (TxpoolContentResult, error) {
+	return a.TxpoolContentContext(context.Background())
+}
+
+// TxpoolContentContext is like TxpoolContent but honors ctx for cancellation and deadlines.
+func (a Anvil) TxpoolContentContext(ctx context.Context) (TxpoolContentResult, error) {
+	res, err := makeRequest[TxpoolContentResult](ctx, a.rpcClient, "txpool_content", []any{})
+	if err != nil {
+		return TxpoolContentResult{}, err
+	}
+	return *res, nil
+}
@@ -0,0 +1,101 @@
+package anvil
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testTxJSON returns the RPC representation of a pending transfer signed by key.
+func testTxJSON(key *ecdsa.PrivateKey, nonce uint64, to common.Address) map[string]any {
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(31337)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(31337),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1e9),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+	})
+	raw, _ := tx.MarshalJSON()
+
+	var fields map[string]any
+	json.Unmarshal(raw, &fields)
+	fields["from"] = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	fields["blockHash"] = nil
+	fields["blockNumber"] = nil
+	return fields
+}
+
+// TestAnvil_TxpoolContentDecode checks decoding of txpool_content into typed transactions.
+func TestAnvil_TxpoolContentDecode(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	txJSON := func(nonce uint64) map[string]any { return testTxJSON(key, nonce, to) }
+
+	body, _ := json.Marshal(map[string]any{
+		"pending": map[string]any{
+			sender.Hex(): map[string]any{"1": txJSON(1), "0": txJSON(0)},
+		},
+		"queued": map[string]any{
+			sender.Hex(): map[string]any{"5": txJSON(5)},
+		},
+	})
+
+	var content TxpoolContentResult
+	if err := json.Unmarshal(body, &content); err != nil {
+		t.Fatalf("decoding txpool_content failed: %v", err)
+	}
+
+	if content.Pending.Len() != 2 || content.Queued.Len() != 1 {
+		t.Fatalf("unexpected counts: pending %d queued %d", content.Pending.Len(), content.Queued.Len())
+	}
+
+	pending := content.Pending.BySender(sender)
+	if pending[0].Nonce() != 0 || pending[1].Nonce() != 1 {
+		t.Fatalf("pending txs not ordered by nonce")
+	}
+	if pending[0].From != sender || *pending[0].To() != to || pending[0].BlockNumber != nil {
+		t.Fatalf("unexpected tx fields: %+v", pending[0])
+	}
+}
+
+// TestAnvil_TxpoolInspectDecode checks parsing of txpool_inspect summaries and status counts.
+func TestAnvil_TxpoolInspectDecode(t *testing.T) {
+	body := `{
+		"pending": {
+			"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266": {
+				"0": "0x0000000000000000000000000000000000000001: 1 wei + 21000 gas × 1000000000 wei",
+				"1": "contract creation: 0 wei + 53000 gas × 1000000000 wei"
+			}
+		},
+		"queued": {}
+	}`
+
+	var inspect TxpoolInspectResult
+	if err := json.Unmarshal([]byte(body), &inspect); err != nil {
+		t.Fatalf("decoding txpool_inspect failed: %v", err)
+	}
+
+	transfer := inspect.Pending[account0][0]
+	if transfer.To == nil || transfer.Value.Int64() != 1 || transfer.Gas != 21000 || transfer.GasPrice.Int64() != 1e9 {
+		t.Fatalf("unexpected summary: %+v", transfer)
+	}
+	if creation := inspect.Pending[account0][1]; creation.To != nil || creation.Gas != 53000 {
+		t.Fatalf("unexpected summary: %+v", creation)
+	}
+
+	var status TxpoolStatusResult
+	if err := json.Unmarshal([]byte(`{"pending":"0x2","queued":"0x1"}`), &status); err != nil {
+		t.Fatalf("decoding txpool_status failed: %v", err)
+	}
+	if status.Pending != 2 || status.Queued != 1 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
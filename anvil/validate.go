@@ -0,0 +1,204 @@
+package anvil
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ConfigError is returned by Config.Validate and lists every problem found.
+type ConfigError struct {
+	Errors []error
+}
+
+func (e *ConfigError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "invalid anvil config: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual problems so they can be inspected with errors.Is and errors.As.
+func (e *ConfigError) Unwrap() []error {
+	return e.Errors
+}
+
+// hardforks are the hardfork names accepted by --hardfork, normalized with normalizeHardfork.
+var hardforks = map[string]bool{
+	"frontier": true, "homestead": true, "dao": true, "tangerine": true,
+	"spuriousdragon": true, "byzantium": true, "constantinople": true,
+	"petersburg": true, "istanbul": true, "muirglacier": true, "berlin": true,
+	"london": true, "arrowglacier": true, "grayglacier": true, "paris": true,
+	"merge": true, "shanghai": true, "cancun": true, "prague": true,
+	"osaka": true, "latest": true,
+
+	// Optimism hardforks, used together with --optimism.
+	"bedrock": true, "regolith": true, "canyon": true, "ecotone": true,
+	"fjord": true, "granite": true, "holocene": true, "isthmus": true,
+	"jovian": true,
+}
+
+// normalizeHardfork lowercases a hardfork name and strips separators, so
+// "Spurious-Dragon" and "spurious_dragon" both match "spuriousdragon".
+func normalizeHardfork(name string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+}
+
+// Validate checks the configuration for contradictory or malformed values
+// that Anvil would otherwise reject with a less helpful error, and reports
+// all of them at once as a *ConfigError. NewWithConfig calls Validate before
+// starting the process.
+func (c *Config) Validate() error {
+	var errs []error
+	problem := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	// Logging
+	if c.quiet && c.verbosity > 0 {
+		problem("SetQuiet and SetVerbosity are mutually exclusive")
+	}
+	if c.verbosity < 0 || c.verbosity > 5 {
+		problem("verbosity must be between 0 and 5, got %d", c.verbosity)
+	}
+	if c.jsonLogs && c.markdownLogs {
+		problem("SetJSONLogs and SetMarkdownLogs are mutually exclusive")
+	}
+	if c.color != "" && c.color != "auto" && c.color != "always" && c.color != "never" {
+		problem("unknown color mode %q, expected auto, always or never", c.color)
+	}
+	if c.logBufferSize < 0 {
+		problem("log buffer size must not be negative, got %d", c.logBufferSize)
+	}
+
+	// Accounts
+	mnemonics := 0
+	for _, set := range []bool{c.mnemonic != "", c.mnemonicRandom, c.mnemonicSeedUnsafe != ""} {
+		if set {
+			mnemonics++
+		}
+	}
+	if mnemonics > 1 {
+		problem("SetMnemonic, SetMnemonicRandom and SetMnemonicSeedUnsafe are mutually exclusive")
+	}
+	if c.mnemonicRandomWords != 0 {
+		if !c.mnemonicRandom {
+			problem("SetMnemonicRandomWords requires SetMnemonicRandom")
+		}
+		switch c.mnemonicRandomWords {
+		case 12, 15, 18, 21, 24:
+		default:
+			problem("mnemonic word count must be 12, 15, 18, 21 or 24, got %d", c.mnemonicRandomWords)
+		}
+	}
+	if c.derivationPath != "" {
+		if _, err := parseDerivationPath(c.derivationPath + "0"); err != nil {
+			problem("%v", err)
+		}
+	}
+	if c.balance != "" && !isDecimal(c.balance) {
+		problem("balance must be a whole number of Ether, got %q", c.balance)
+	}
+
+	// Mining
+	if c.noMining && c.blockTime != 0 {
+		problem("SetNoMining and SetBlockTime are mutually exclusive")
+	}
+	if c.mixedMining && c.blockTime == 0 {
+		problem("SetMixedMining requires SetBlockTime")
+	}
+	if c.order != "" && c.order != "fees" && c.order != "fifo" {
+		problem("unknown transaction order %q, expected fees or fifo", c.order)
+	}
+
+	// State
+	if c.statePath != "" && (c.loadStatePath != "" || c.dumpStatePath != "") {
+		problem("SetStatePath cannot be combined with SetLoadStatePath or SetDumpStatePath")
+	}
+	if c.pruneHistoryStates != 0 && !c.pruneHistory {
+		problem("SetPruneHistoryStates requires SetPruneHistory")
+	}
+	if c.ipcPath != "" && !c.ipc {
+		problem("SetIPCPath requires the IPC server to be enabled")
+	}
+
+	// Forking
+	if c.forkURL == "" {
+		forkOnly := []struct {
+			setter string
+			set    bool
+		}{
+			{"SetForkBlockNumber", c.forkBlockNumber != 0},
+			{"SetForkChainID", c.forkChainID != 0},
+			{"SetForkHeaders", len(c.forkHeaders) > 0},
+			{"SetForkRetryBackoff", c.forkRetryBackoff != ""},
+			{"SetForkTransactionHash", c.forkTransactionHash != ""},
+		}
+		for _, opt := range forkOnly {
+			if opt.set {
+				problem("%s requires SetForkURL", opt.setter)
+			}
+		}
+	}
+	if c.forkBlockNumber != 0 && c.forkTransactionHash != "" {
+		problem("SetForkBlockNumber and SetForkTransactionHash are mutually exclusive")
+	}
+	for _, h := range c.forkHeaders {
+		if !strings.Contains(h, ":") {
+			problem("fork header %q must have the form \"Name: value\"", h)
+		}
+	}
+	if c.timeout != "" && !isDecimal(c.timeout) {
+		problem("timeout must be a number of milliseconds, got %q", c.timeout)
+	}
+
+	// Environment
+	if c.hardfork != "" && !hardforks[normalizeHardfork(c.hardfork)] {
+		problem("unknown hardfork %q", c.hardfork)
+	}
+	if c.blockBaseFeePerGas != "" && !isQuantity(c.blockBaseFeePerGas) {
+		problem("base fee must be a decimal or 0x-prefixed hex number, got %q", c.blockBaseFeePerGas)
+	}
+	if c.gasPrice != "" && !isQuantity(c.gasPrice) {
+		problem("gas price must be a decimal or 0x-prefixed hex number, got %q", c.gasPrice)
+	}
+	if c.celo && c.optimism {
+		problem("SetCelo and SetOptimism are mutually exclusive")
+	}
+
+	// Lifecycle
+	if c.startupTimeout < 0 {
+		problem("startup timeout must not be negative, got %s", c.startupTimeout)
+	}
+	if c.shutdownTimeout < 0 {
+		problem("shutdown timeout must not be negative, got %s", c.shutdownTimeout)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ConfigError{Errors: errs}
+}
+
+// isDecimal reports whether s is a non-empty string of decimal digits.
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isQuantity reports whether s is a decimal or 0x-prefixed hex number.
+func isQuantity(s string) bool {
+	if hex, ok := strings.CutPrefix(s, "0x"); ok {
+		_, ok := new(big.Int).SetString(hex, 16)
+		return ok && !strings.HasPrefix(hex, "-")
+	}
+	return isDecimal(s)
+}
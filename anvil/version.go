@@ -0,0 +1,186 @@
+package anvil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrBinaryNotFound is returned when no anvil binary can be located.
+var ErrBinaryNotFound = errors.New("anvil binary not found")
+
+// FindBinary returns the path of the anvil binary NewWithConfig would run for
+// config. The path set with SetBinaryPath is used as is; otherwise anvil is
+// looked up on PATH, then in $FOUNDRY_DIR/bin and finally in ~/.foundry/bin,
+// where foundryup installs it by default.
+//
+// If no binary can be found, the returned error wraps ErrBinaryNotFound.
+func FindBinary(config *Config) (string, error) {
+	if config.binaryPath != "" {
+		if _, err := os.Stat(config.binaryPath); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrBinaryNotFound, err)
+		}
+		return config.binaryPath, nil
+	}
+
+	if path, err := exec.LookPath("anvil"); err == nil {
+		return path, nil
+	}
+
+	var candidates []string
+	if dir := os.Getenv("FOUNDRY_DIR"); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, "bin", "anvil"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".foundry", "bin", "anvil"))
+	}
+
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w on PATH, in $FOUNDRY_DIR/bin or ~/.foundry/bin", ErrBinaryNotFound)
+}
+
+// VersionCheck controls what NewWithConfig does when the installed Anvil is
+// older than a configured flag requires.
+type VersionCheck int
+
+const (
+	// VersionCheckWarn writes a warning to the log writer (or os.Stderr) and
+	// starts Anvil anyway. This is the default.
+	VersionCheckWarn VersionCheck = iota
+	// VersionCheckStrict refuses to start Anvil and returns a *VersionError.
+	VersionCheckStrict
+	// VersionCheckOff skips running `anvil --version` entirely.
+	VersionCheckOff
+)
+
+// Version is a parsed `anvil --version` output.
+type Version struct {
+	Major, Minor, Patch int
+	// Raw is the full first line printed by `anvil --version`.
+	Raw string
+}
+
+// versionRe matches the semantic version in both the current
+// ("anvil Version: 1.3.0-stable") and the pre-1.0 ("anvil 0.2.0 (abc 2024-...)") formats.
+var versionRe = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+
+// ParseVersion parses the output of `anvil --version`.
+func ParseVersion(output string) (Version, error) {
+	first, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+
+	m := versionRe.FindStringSubmatch(first)
+	if m == nil {
+		return Version{}, fmt.Errorf("error parsing anvil version from %q", first)
+	}
+
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3])
+
+	return Version{Major: major, Minor: minor, Patch: patch, Raw: first}, nil
+}
+
+// String returns the version as "major.minor.patch".
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is the same as or newer than other.
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// flagVersions lists the first stable Anvil release known to support flags
+// the wrapper can pass. Flags not listed here are assumed to be supported by
+// every release. Only add a flag together with a reference to the foundry
+// release notes that introduce it: with VersionCheckStrict a wrong entry
+// refuses a working install.
+//
+// No entry has been verified against the release notes yet, so the table is
+// empty and NewWithConfig does not run `anvil --version` at all.
+var flagVersions = map[string]Version{}
+
+// VersionError is returned by NewWithConfig with VersionCheckStrict when the
+// installed Anvil is too old for a configured flag.
+type VersionError struct {
+	Flag     string
+	Required Version
+	Found    Version
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("%s requires anvil >= %s, found %s", e.Flag, e.Required, e.Found)
+}
+
+// binaryVersion runs `anvil --version` for the binary at path.
+func binaryVersion(ctx context.Context, path string) (Version, error) {
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return Version{}, fmt.Errorf("error running %s --version: %v", path, err)
+	}
+	return ParseVersion(string(out))
+}
+
+// startupVersion runs `anvil --version` for the binary at path if any flag
+// in args is listed in table, and checks the flags against it. With
+// VersionCheckWarn, problems, including a failing `anvil --version`, are
+// written to w and only the version is returned.
+func startupVersion(ctx context.Context, path string, args []string, table map[string]Version, mode VersionCheck, w io.Writer) (Version, error) {
+	if mode == VersionCheckOff || !slices.ContainsFunc(args, func(arg string) bool {
+		_, ok := table[arg]
+		return ok
+	}) {
+		return Version{}, nil
+	}
+
+	version, err := binaryVersion(ctx, path)
+	if err != nil {
+		if mode == VersionCheckStrict {
+			return Version{}, err
+		}
+		fmt.Fprintf(w, "warning: skipping anvil version check: %v\n", err)
+		return Version{}, nil
+	}
+	return version, checkVersion(version, args, table, mode, w)
+}
+
+// checkVersion compares the flags in args against the installed version,
+// using the minimum versions in table.
+// With VersionCheckWarn, problems are written to w and nil is returned.
+func checkVersion(version Version, args []string, table map[string]Version, mode VersionCheck, w io.Writer) error {
+	var errs []error
+	for _, arg := range args {
+		required, ok := table[arg]
+		if !ok || version.AtLeast(required) {
+			continue
+		}
+		errs = append(errs, &VersionError{Flag: arg, Required: required, Found: version})
+	}
+
+	if mode == VersionCheckStrict {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		fmt.Fprintf(w, "warning: %v\n", err)
+	}
+	return nil
+}
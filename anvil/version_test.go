@@ -0,0 +1,122 @@
+package anvil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAnvil_ParseVersion checks parsing of current and pre-1.0 version output.
+func TestAnvil_ParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"anvil Version: 1.3.5-stable\nCommit SHA: 9979a41\nBuild Profile: maxperf\n", "1.3.5"},
+		{"anvil 0.2.0 (a2bc4c4 2024-10-15T00:21:41.283264000Z)\n", "0.2.0"},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.output)
+		if err != nil {
+			t.Fatalf("ParseVersion(%q) failed: %v", tt.output, err)
+		}
+		if v.String() != tt.want {
+			t.Errorf("ParseVersion(%q) = %s; want %s", tt.output, v, tt.want)
+		}
+	}
+
+	if _, err := ParseVersion("anvil nightly"); err == nil {
+		t.Errorf("ParseVersion accepted output without a version")
+	}
+}
+
+// TestAnvil_VersionCheck checks that an outdated binary is refused in strict
+// mode and reported in warn mode, and that --version only runs when needed.
+func TestAnvil_VersionCheck(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "anvil")
+	marker := filepath.Join(dir, "ran")
+	script := "#!/bin/sh\ntouch " + marker + "\necho 'anvil 0.2.0 (a2bc4c4 2024-10-15T00:21:41.283264000Z)'\n"
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	table := map[string]Version{"--mixed-mining": {Major: 1, Minor: 1, Patch: 0}}
+	args := getArgs(NewConfig().SetBlockTime(1).SetMixedMining(true))
+	ctx := context.Background()
+
+	_, err := startupVersion(ctx, binary, args, table, VersionCheckStrict, io.Discard)
+	var versionErr *VersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("expected *VersionError, got %T: %v", err, err)
+	}
+	if versionErr.Flag != "--mixed-mining" || versionErr.Found.String() != "0.2.0" {
+		t.Fatalf("unexpected version error: %+v", versionErr)
+	}
+
+	var warnings bytes.Buffer
+	version, err := startupVersion(ctx, binary, args, table, VersionCheckWarn, &warnings)
+	if err != nil || version.String() != "0.2.0" {
+		t.Fatalf("warn mode returned %s, %v", version, err)
+	}
+	if !strings.Contains(warnings.String(), "--mixed-mining requires anvil >= 1.1.0") {
+		t.Fatalf("unexpected warnings: %q", warnings.String())
+	}
+
+	warnings.Reset()
+	broken := filepath.Join(dir, "broken")
+	if err := os.WriteFile(broken, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := startupVersion(ctx, broken, args, table, VersionCheckWarn, &warnings); err != nil {
+		t.Fatalf("warn mode failed on a broken --version: %v", err)
+	}
+	if !strings.Contains(warnings.String(), "skipping anvil version check") {
+		t.Fatalf("broken --version was not reported: %q", warnings.String())
+	}
+	if _, err := startupVersion(ctx, broken, args, table, VersionCheckStrict, io.Discard); err == nil {
+		t.Fatalf("strict mode accepted a broken --version")
+	}
+
+	os.Remove(marker)
+	if _, err := startupVersion(ctx, binary, getArgs(NewConfig()), table, VersionCheckStrict, io.Discard); err != nil {
+		t.Fatalf("startupVersion failed without versioned flags: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatalf("anvil --version ran although no configured flag needs it")
+	}
+}
+
+// TestAnvil_FindBinary checks the $FOUNDRY_DIR fallback when anvil is not on PATH.
+func TestAnvil_FindBinary(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "bin", "anvil")
+	if err := os.MkdirAll(filepath.Dir(binary), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("FOUNDRY_DIR", dir)
+
+	path, err := FindBinary(NewConfig())
+	if err != nil {
+		t.Fatalf("FindBinary failed: %v", err)
+	}
+	if path != binary {
+		t.Fatalf("FindBinary = %s; want %s", path, binary)
+	}
+
+	_, err = FindBinary(NewConfig().SetBinaryPath(filepath.Join(dir, "missing")))
+	if !errors.Is(err, ErrBinaryNotFound) {
+		t.Fatalf("expected ErrBinaryNotFound, got %v", err)
+	}
+}